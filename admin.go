@@ -0,0 +1,269 @@
+package verbose
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Settings is the part of a Verb that can be inspected and changed at run time.
+type Settings struct {
+	V         bool   `json:"v"`
+	Out       string `json:"out"`
+	Dformat   string `json:"dformat"`
+	Strftime  string `json:"strftime,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Delimeter string `json:"delimeter"`
+	PrintDate bool   `json:"printDate"`
+	PrintLine bool   `json:"printLine"`
+	// RevertAt is set while a temporary change is in effect.
+	RevertAt *time.Time `json:"revertAt,omitempty"`
+}
+
+// SettingsChange is the body of a POST to AdminHandler. Fields left out are not changed. Location is a zone name like
+// "UTC" or "Europe/Paris", or "Local" for the host's zone.
+type SettingsChange struct {
+	V         *bool   `json:"v"`
+	Out       *string `json:"out"`
+	Dformat   *string `json:"dformat"`
+	Strftime  *string `json:"strftime"`
+	Location  *string `json:"location"`
+	Delimeter *string `json:"delimeter"`
+	PrintDate *bool   `json:"printDate"`
+	PrintLine *bool   `json:"printLine"`
+	// For makes the change temporary, e.g. "10m". The previous settings come back when it runs out.
+	For string `json:"for"`
+}
+
+// AdminHandler returns an http.Handler that shows the Verb's settings and lets you change them in a running service.
+// Mount it on your own mux, preferably one only listening on localhost:
+//
+//	http.Handle("/debug/verbose", verb.AdminHandler(os.Getenv("VERBOSE_TOKEN")))
+//
+// GET returns the Settings as JSON. POST takes a SettingsChange as JSON and needs "Authorization: Bearer <token>".
+// If token is empty every POST is refused.
+//
+//	curl -H "Authorization: Bearer $VERBOSE_TOKEN" -d '{"v":true,"printLine":true,"for":"10m"}' localhost:6060/debug/verbose
+func (v *Verb) AdminHandler(token string) http.Handler {
+	return &adminHandler{verb: v, token: token}
+}
+
+type adminHandler struct {
+	verb  *Verb
+	token string
+
+	mu       sync.Mutex
+	saved    *Verb
+	revertAt time.Time
+	timer    *time.Timer
+	// gen counts changes, so a revert whose timer fired just as a newer change came in doesn't undo it.
+	gen uint64
+}
+
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		if !h.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="verbose"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var change SettingsChange
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&change); err != nil {
+			http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.apply(change, r.RemoteAddr); err != nil {
+			http.Error(w, "bad request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(h.settings())
+}
+
+func (h *adminHandler) authorized(r *http.Request) bool {
+	if h.token == "" {
+		return false
+	}
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) == 1
+}
+
+func (h *adminHandler) settings() Settings {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.verb.Settings()
+	if h.saved != nil {
+		at := h.revertAt
+		s.RevertAt = &at
+	}
+	return s
+}
+
+// apply changes the Verb's settings. A temporary change saves the settings from before the first temporary change,
+// a permanent one drops them.
+func (h *adminHandler) apply(c SettingsChange, from string) error {
+	var d time.Duration
+	if c.For != "" {
+		var err error
+		if d, err = time.ParseDuration(c.For); err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration %q", c.For)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	reason := "settings changed by " + from
+	if d > 0 {
+		reason += " for " + d.String()
+	}
+	mu.Lock()
+	v := h.verb
+	saved := Verb{V: v.Enabled(), Out: v.Out, Dformat: v.Dformat, Strftime: v.Strftime, Location: v.Location,
+		Delimeter: v.Delimeter, PrintDate: v.PrintDate, PrintLine: v.PrintLine}
+	mu.Unlock()
+	if err := h.verb.change(c, reason); err != nil {
+		return err
+	}
+	h.gen++
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+	}
+	if d <= 0 {
+		h.saved = nil
+		return nil
+	}
+	if h.saved == nil {
+		h.saved = &saved
+	}
+	h.revertAt = time.Now().Add(d)
+	gen := h.gen
+	h.timer = time.AfterFunc(d, func() { h.revert(gen) })
+	return nil
+}
+
+// revert restores the settings saved by a temporary change, unless the settings have changed again since change gen.
+func (h *adminHandler) revert(gen uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.saved == nil || gen != h.gen {
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	v := h.verb
+	v.logChange("temporary settings expired, reverting")
+	v.SetV(h.saved.V)
+	v.Out = h.saved.Out
+	v.Dformat = h.saved.Dformat
+	v.Strftime = h.saved.Strftime
+	v.Location = h.saved.Location
+	v.Delimeter = h.saved.Delimeter
+	v.PrintDate = h.saved.PrintDate
+	v.PrintLine = h.saved.PrintLine
+	h.saved = nil
+	h.timer = nil
+}
+
+// Settings returns a snapshot of the Verb's current settings.
+func (v *Verb) Settings() Settings {
+	mu.Lock()
+	defer mu.Unlock()
+	return Settings{
+		V:         v.Enabled(),
+		Out:       describeWriter(v.Out),
+		Dformat:   v.Dformat,
+		Strftime:  v.Strftime,
+		Location:  locationName(v.Location),
+		Delimeter: v.Delimeter,
+		PrintDate: v.PrintDate,
+		PrintLine: v.PrintLine,
+	}
+}
+
+// change applies c to the Verb and logs it with reason. c.For is ignored.
+func (v *Verb) change(c SettingsChange, reason string) error {
+	var out io.Writer
+	if c.Out != nil {
+		switch *c.Out {
+		case "stdout":
+			out = os.Stdout
+		case "stderr":
+			out = os.Stderr
+		default:
+			return fmt.Errorf("out must be stdout or stderr, not %q", *c.Out)
+		}
+	}
+	var loc *time.Location
+	if c.Location != nil && *c.Location != "" && !strings.EqualFold(*c.Location, "local") {
+		var err error
+		if loc, err = time.LoadLocation(*c.Location); err != nil {
+			return fmt.Errorf("unknown location %q", *c.Location)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if c.V != nil {
+		v.SetV(*c.V)
+	}
+	if out != nil {
+		v.Out = out
+	}
+	if c.Dformat != nil {
+		v.Dformat = *c.Dformat
+		v.Strftime = ""
+	}
+	if c.Strftime != nil {
+		v.setStrftime(*c.Strftime)
+	}
+	if c.Location != nil {
+		v.Location = loc
+	}
+	if c.Delimeter != nil {
+		v.Delimeter = *c.Delimeter
+	}
+	if c.PrintDate != nil {
+		v.PrintDate = *c.PrintDate
+	}
+	if c.PrintLine != nil {
+		v.PrintLine = *c.PrintLine
+	}
+	v.logChange("%s", reason)
+	return nil
+}
+
+// locationName returns the name of loc, or "" for nil.
+func locationName(loc *time.Location) string {
+	if loc == nil {
+		return ""
+	}
+	return loc.String()
+}
+
+// describeWriter names an output target for people to read.
+func describeWriter(w io.Writer) string {
+	switch w {
+	case nil, os.Stdout:
+		return "stdout"
+	case os.Stderr:
+		return "stderr"
+	}
+	if f, ok := w.(*os.File); ok {
+		return f.Name()
+	}
+	return fmt.Sprintf("%T", w)
+}
@@ -0,0 +1,96 @@
+package verbose
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func adminRequest(t *testing.T, h http.Handler, method, token, body string) (int, Settings) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/debug/verbose", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var s Settings
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&s); err != nil {
+			t.Fatalf("%s: decoding response: %v", method, err)
+		}
+	}
+	return rec.Code, s
+}
+
+func TestVerb_AdminHandler(t *testing.T) {
+	dformat := "%F"
+	v := New(io.Discard, dformat)
+	h := v.AdminHandler("sekrit")
+
+	code, s := adminRequest(t, h, http.MethodGet, "", "")
+	if code != http.StatusOK || s.V || s.Dformat != "2006-01-02" || s.RevertAt != nil {
+		t.Fatalf("GET = %d %+v", code, s)
+	}
+	if code, _ := adminRequest(t, h, http.MethodPost, "", `{"v":true}`); code != http.StatusUnauthorized {
+		t.Errorf("POST without token = %d, want 401", code)
+	}
+	if code, _ := adminRequest(t, h, http.MethodPost, "wrong", `{"v":true}`); code != http.StatusUnauthorized {
+		t.Errorf("POST with wrong token = %d, want 401", code)
+	}
+	if code, _ := adminRequest(t, h, http.MethodPost, "sekrit", `{"out":"/etc/passwd"}`); code != http.StatusBadRequest {
+		t.Errorf("POST with file out = %d, want 400", code)
+	}
+	if v.Enabled() {
+		t.Fatal("rejected POST changed V")
+	}
+
+	code, s = adminRequest(t, h, http.MethodPost, "sekrit", `{"v":true,"printLine":true,"strftime":"%T"}`)
+	if code != http.StatusOK || !s.V || !s.PrintLine || s.Dformat != "15:04:05" {
+		t.Fatalf("POST = %d %+v", code, s)
+	}
+
+	code, s = adminRequest(t, h, http.MethodPost, "sekrit", `{"v":false,"for":"20ms"}`)
+	if code != http.StatusOK || s.V || s.RevertAt == nil {
+		t.Fatalf("temporary POST = %d %+v", code, s)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !v.Enabled() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, s = adminRequest(t, h, http.MethodGet, "", ""); !s.V || s.RevertAt != nil {
+		t.Errorf("after revert %+v, want V back on", s)
+	}
+
+	if code, _ := adminRequest(t, v.AdminHandler(""), http.MethodPost, "", `{"v":true}`); code != http.StatusUnauthorized {
+		t.Errorf("POST with no token configured = %d, want 401", code)
+	}
+}
+
+func TestAdminHandler_StaleRevert(t *testing.T) {
+	v := New(io.Discard)
+	h := v.AdminHandler("sekrit").(*adminHandler)
+	if err := h.apply(SettingsChange{V: ptr(true), For: "1h"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	first := h.gen
+	if err := h.apply(SettingsChange{PrintLine: ptr(true), For: "1h"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	// The first timer fired and was waiting for the lock while the second change was made.
+	h.revert(first)
+	if s := v.Settings(); !s.V || !s.PrintLine {
+		t.Errorf("a stale revert undid the newer change: %+v", s)
+	}
+	h.timer.Stop()
+	h.revert(h.gen)
+	if s := v.Settings(); s.V || s.PrintLine {
+		t.Errorf("revert didn't restore the settings from before both changes: %+v", s)
+	}
+}
+
+func ptr[T any](x T) *T { return &x }
@@ -0,0 +1,118 @@
+package verbose
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	emailRe = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	ipv4Re  = regexp.MustCompile(`\b(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)(?:\.(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)){3}\b`)
+	ipv6Re  = regexp.MustCompile(`[0-9A-Fa-f]*:[0-9A-Fa-f:.]*:[0-9A-Fa-f.]*`)
+	hostRe  = regexp.MustCompile(`\b(?:[A-Za-z0-9](?:[A-Za-z0-9-]{0,61}[A-Za-z0-9])?\.)+([a-z]{2,63})\b`)
+)
+
+// notTLD holds file extensions that look like a top level domain, so file names like main.go are not taken for hosts.
+var notTLD = map[string]bool{
+	"go": true, "mod": true, "sum": true, "txt": true, "log": true, "json": true, "yaml": true, "yml": true,
+	"toml": true, "xml": true, "csv": true, "md": true, "html": true, "js": true, "ts": true, "py": true,
+	"sh": true, "conf": true, "cfg": true, "ini": true, "sql": true, "gz": true, "tar": true, "zip": true,
+	"pem": true, "crt": true, "key": true, "out": true, "tmp": true, "bak": true, "pid": true, "sock": true,
+}
+
+// Anonymizer replaces IP addresses, email addresses and host names with stable pseudonyms, so a log can be shared
+// without exposing who or what it talks about. The same value always gets the same pseudonym for a given salt,
+// so patterns stay traceable: 10.1.2.3 might become ip-5c1d09e2 on every line it appears on.
+//
+//	verb.Anonymizer = verbose.NewAnonymizer("")
+//	verb.Println("Connected to", host, "from", addr)
+type Anonymizer struct {
+	// IPs, Emails and Hosts select what is replaced. NewAnonymizer turns them all on.
+	IPs    bool
+	Emails bool
+	Hosts  bool
+
+	salt  []byte
+	mu    sync.Mutex
+	names []string
+	// namesRe matches any of names as a word, nil when there are none.
+	namesRe *regexp.Regexp
+}
+
+// NewAnonymizer returns an Anonymizer keyed by salt. With an empty salt a random one is used, so pseudonyms are
+// only stable within the run. Use the same salt across runs to correlate logs from several of them.
+func NewAnonymizer(salt string) *Anonymizer {
+	a := &Anonymizer{IPs: true, Emails: true, Hosts: true, salt: []byte(salt)}
+	if salt == "" {
+		a.salt = make([]byte, 32)
+		rand.Read(a.salt)
+	}
+	return a
+}
+
+// AddHost adds host names that don't contain a dot, such as db01, to be replaced wherever they appear as a word.
+func (a *Anonymizer) AddHost(names ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.names = append(a.names, names...)
+	quoted := make([]string, len(a.names))
+	for i, name := range a.names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	a.namesRe = regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// Pseudonym returns the stable pseudonym for value. kind is used as the pseudonym prefix, e.g. "ip" or "host".
+// Pseudonyms are computed each time rather than remembered, so a long running process doesn't collect every value
+// it has seen.
+func (a *Anonymizer) Pseudonym(kind, value string) string {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(kind + ":" + strings.ToLower(value)))
+	return kind + "-" + hex.EncodeToString(mac.Sum(nil)[:4])
+}
+
+// Anonymize returns s with every email address, IP address and host name replaced by its pseudonym.
+func (a *Anonymizer) Anonymize(s string) string {
+	if a == nil {
+		return s
+	}
+	if a.Emails {
+		s = emailRe.ReplaceAllStringFunc(s, func(m string) string {
+			return a.Pseudonym("email", m)
+		})
+	}
+	if a.IPs {
+		s = ipv6Re.ReplaceAllStringFunc(s, func(m string) string {
+			if addr, err := netip.ParseAddr(m); err != nil || !addr.Is6() {
+				return m
+			}
+			return a.Pseudonym("ip", m)
+		})
+		s = ipv4Re.ReplaceAllStringFunc(s, func(m string) string {
+			return a.Pseudonym("ip", m)
+		})
+	}
+	if a.Hosts {
+		s = hostRe.ReplaceAllStringFunc(s, func(m string) string {
+			if notTLD[m[strings.LastIndexByte(m, '.')+1:]] {
+				return m
+			}
+			return a.Pseudonym("host", m)
+		})
+		a.mu.Lock()
+		re := a.namesRe
+		a.mu.Unlock()
+		if re != nil {
+			s = re.ReplaceAllStringFunc(s, func(m string) string {
+				return a.Pseudonym("host", m)
+			})
+		}
+	}
+	return s
+}
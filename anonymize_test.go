@@ -0,0 +1,66 @@
+package verbose
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAnonymizer(t *testing.T) {
+	a := NewAnonymizer("salt")
+	a.AddHost("db01")
+	in := "bob@example.com connected from 10.1.2.3:5432 and fe80::1 to api.example.com, db01 via main.go at 15:04:05"
+	got := a.Anonymize(in)
+	for _, leak := range []string{"bob@", "10.1.2.3", "fe80::1", "api.example.com", "db01"} {
+		if strings.Contains(got, leak) {
+			t.Errorf("Anonymize leaked %q: %s", leak, got)
+		}
+	}
+	for _, keep := range []string{"main.go", "15:04:05", ":5432"} {
+		if !strings.Contains(got, keep) {
+			t.Errorf("Anonymize removed %q: %s", keep, got)
+		}
+	}
+	if again := a.Anonymize(in); again != got {
+		t.Errorf("pseudonyms not stable:\n%s\n%s", got, again)
+	}
+	if other := NewAnonymizer("pepper").Anonymize(in); other == got {
+		t.Errorf("different salts gave the same pseudonyms: %s", got)
+	}
+	b := NewAnonymizer("salt")
+	b.AddHost("db01")
+	if b := b.Anonymize(in); b != got {
+		t.Errorf("same salt gave different pseudonyms:\n%s\n%s", got, b)
+	}
+}
+
+func TestAnonymizer_AddHost(t *testing.T) {
+	a := NewAnonymizer("salt")
+	a.AddHost("db01")
+	a.AddHost("cache-2", "x.y")
+	got := a.Anonymize("DB01 db01x cache-2 x.y xzy")
+	if strings.Contains(got, "DB01 ") || strings.Contains(got, "cache-2") || strings.Contains(got, "x.y") {
+		t.Errorf("host names left in %q", got)
+	}
+	if !strings.Contains(got, "db01x") || !strings.Contains(got, "xzy") {
+		t.Errorf("only whole names should be replaced: %q", got)
+	}
+	if a.Pseudonym("host", "DB01") != a.Pseudonym("host", "db01") {
+		t.Error("pseudonyms depend on case")
+	}
+}
+
+func TestVerb_PrintlnAnonymize(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.V = true
+	v.Anonymizer = NewAnonymizer("salt")
+	v.Println("client", "10.1.2.3")
+	v.Println("again", "10.1.2.3")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	tok := v.Anonymizer.Pseudonym("ip", "10.1.2.3")
+	if len(lines) != 2 || lines[0] != "client "+tok || lines[1] != "again "+tok {
+		t.Errorf("got %q, want both lines to use %s", lines, tok)
+	}
+}
@@ -0,0 +1,245 @@
+package verbose
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// Overflow is what an asynchronous Verb does with a message when its queue is full.
+type Overflow int
+
+const (
+	// Block waits for room in the queue, so nothing is lost but a slow writer slows the program down.
+	Block Overflow = iota
+	// DropNewest throws away the message being printed.
+	DropNewest
+	// DropOldest throws away the oldest queued message to make room.
+	DropOldest
+)
+
+func (o Overflow) String() string {
+	switch o {
+	case Block:
+		return "block"
+	case DropNewest:
+		return "drop newest"
+	case DropOldest:
+		return "drop oldest"
+	}
+	return fmt.Sprintf("Overflow(%d)", int(o))
+}
+
+// queued is a line waiting to be written.
+type queued struct {
+	w io.Writer
+	b []byte
+}
+
+// async is the queue and writer goroutine of an asynchronous Verb.
+type async struct {
+	c       chan queued
+	policy  Overflow
+	dropped atomic.Int64
+	exited  chan struct{}
+
+	// sending is held for reading by a put waiting for room without mu, and for writing while close closes c, so
+	// such a put never sends on a closed channel. closed is set once c is closed.
+	sending sync.RWMutex
+	closed  bool
+
+	mu      sync.Mutex // guards the fields below
+	cond    sync.Cond
+	sent    uint64    // messages queued
+	written uint64    // messages written or dropped after being queued
+	last    io.Writer // where the dropped notice goes at close
+	err     error
+}
+
+// Async makes the Verb hand its output to a goroutine through a queue of size messages, so printing doesn't wait for
+// a slow file or network writer. policy says what happens when the queue is full. When messages are dropped the
+// writer says how many before the next message it writes, or when the Verb is closed.
+//
+// Call Close, or Flush, before the program exits or queued messages are lost. ErrOut does it before exiting.
+//
+//	verb.Async(1000, verbose.DropOldest)
+//	defer verb.Close()
+func (v *Verb) Async(size int, policy Overflow) {
+	if size < 1 {
+		size = 1
+	}
+	q := &async{c: make(chan queued, size), policy: policy, exited: make(chan struct{})}
+	q.cond.L = &q.mu
+	go q.run()
+	mu.Lock()
+	old := v.async
+	v.async = q
+	mu.Unlock()
+	if old != nil {
+		old.close()
+	}
+}
+
+// Flush waits until every message printed so far has been written, and returns the first write error since the
+// last Flush. It does nothing when the Verb is not asynchronous.
+func (v *Verb) Flush() error {
+	mu.Lock()
+	q := v.async
+	mu.Unlock()
+	if q == nil {
+		return nil
+	}
+	return q.flush()
+}
+
+// Close flushes the queue and stops the writer goroutine; the Verb writes synchronously again afterwards. It does not
+// close Out. It returns the first write error since the last Flush.
+func (v *Verb) Close() error {
+	mu.Lock()
+	q := v.async
+	v.async = nil
+	mu.Unlock()
+	if q == nil {
+		return nil
+	}
+	return q.close()
+}
+
+// write writes a finished line to w, or queues it when the Verb is asynchronous. mu must be held. It is released
+// while writing, since w may print too, and while waiting for room in the queue, so b must not be read afterwards.
+func (v *Verb) write(w io.Writer, b []byte) {
+	if v.async == nil {
+		line := getBuf()
+		*line = append(*line, b...)
+		outMu := v.writeLock()
+		mu.Unlock()
+		outMu.Lock()
+		w.Write(*line)
+		outMu.Unlock()
+		putBuf(line)
+		mu.Lock()
+		return
+	}
+	v.async.put(queued{w, append([]byte(nil), b...)})
+}
+
+// defaultOutMu serializes the writes of Verbs not made with New.
+var defaultOutMu sync.Mutex
+
+// writeLock returns the lock held while writing to the Verb's output.
+func (v *Verb) writeLock() *sync.Mutex {
+	if v.outMu != nil {
+		return v.outMu
+	}
+	return &defaultOutMu
+}
+
+// put queues m according to the overflow policy. It is only called with mu held, so there is one sender at a time,
+// except that with Block a full queue is waited on with mu released: the writer may be slow, and other Verbs and
+// goroutines shouldn't wait with it. mu is held again when put returns.
+func (q *async) put(m queued) {
+	q.mu.Lock()
+	q.sent++
+	q.mu.Unlock()
+	switch q.policy {
+	case DropNewest:
+		select {
+		case q.c <- m:
+		default:
+			q.dropped.Add(1)
+			q.done(m.w, nil)
+		}
+	case DropOldest:
+		for {
+			select {
+			case q.c <- m:
+				return
+			default:
+			}
+			select {
+			case old := <-q.c:
+				q.dropped.Add(1)
+				q.done(old.w, nil)
+			default:
+			}
+		}
+	default:
+		select {
+		case q.c <- m:
+		default:
+			mu.Unlock()
+			q.wait(m)
+			mu.Lock()
+		}
+	}
+}
+
+// wait queues m, waiting for room. If the queue was closed meanwhile, m is written directly instead.
+func (q *async) wait(m queued) {
+	q.sending.RLock()
+	defer q.sending.RUnlock()
+	if q.closed {
+		_, err := m.w.Write(m.b)
+		q.done(m.w, err)
+		return
+	}
+	q.c <- m
+}
+
+// run writes queued messages until the queue is closed.
+func (q *async) run() {
+	defer close(q.exited)
+	for m := range q.c {
+		q.notice(m.w)
+		_, err := m.w.Write(m.b)
+		q.done(m.w, err)
+	}
+	q.mu.Lock()
+	w := q.last
+	q.mu.Unlock()
+	if w != nil {
+		q.notice(w)
+	}
+}
+
+// notice writes how many messages were dropped since the last notice, if any.
+func (q *async) notice(w io.Writer) {
+	if n := q.dropped.Swap(0); n > 0 {
+		fmt.Fprintf(w, "verbose: %d messages dropped\n", n)
+	}
+}
+
+// done counts a queued message as handled. w is the writer it was for and err the result of writing it.
+func (q *async) done(w io.Writer, err error) {
+	q.mu.Lock()
+	q.written++
+	if w != nil {
+		q.last = w
+	}
+	if q.err == nil {
+		q.err = err
+	}
+	q.cond.Broadcast()
+	q.mu.Unlock()
+}
+
+func (q *async) flush() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for target := q.sent; q.written < target; {
+		q.cond.Wait()
+	}
+	err := q.err
+	q.err = nil
+	return err
+}
+
+func (q *async) close() error {
+	q.sending.Lock()
+	q.closed = true
+	close(q.c)
+	q.sending.Unlock()
+	<-q.exited
+	return q.flush()
+}
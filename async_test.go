@@ -0,0 +1,150 @@
+package verbose
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateWriter records what is written to it. When gate is set each Write signals entered and waits for gate.
+type gateWriter struct {
+	gate, entered chan struct{}
+	err           error
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	if w.gate != nil {
+		w.entered <- struct{}{}
+		<-w.gate
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lines = append(w.lines, strings.TrimSuffix(string(p), "\n"))
+	return len(p), w.err
+}
+
+func (w *gateWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return strings.Join(w.lines, "\n")
+}
+
+func TestVerb_Async(t *testing.T) {
+	needsVerbose(t)
+	var out gateWriter
+	v := New(&out)
+	v.V = true
+	v.Async(2, Block)
+	var want []string
+	for i := 0; i < 10; i++ {
+		v.Println("message", i)
+		want = append(want, fmt.Sprint("message ", i))
+	}
+	if err := v.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+	v.Println("sync again")
+	if got := out.String(); !strings.HasSuffix(got, "\nsync again") {
+		t.Errorf("after Close got %q", got)
+	}
+}
+
+func TestVerb_AsyncDrop(t *testing.T) {
+	needsVerbose(t)
+	for _, tt := range []struct {
+		policy Overflow
+		want   string
+	}{
+		{DropNewest, "blocked\nverbose: 3 messages dropped\nqueued 0\nqueued 1"},
+		{DropOldest, "blocked\nverbose: 3 messages dropped\nqueued 3\nqueued 4"},
+	} {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			out := gateWriter{gate: make(chan struct{}), entered: make(chan struct{}, 10)}
+			v := New(&out)
+			v.V = true
+			v.Async(2, tt.policy)
+			v.Println("blocked")
+			<-out.entered
+			// The writer is stuck on "blocked", queue five more into a queue of two.
+			for i := 0; i < 5; i++ {
+				v.Println("queued", i)
+			}
+			close(out.gate)
+			if err := v.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerb_AsyncBlock(t *testing.T) {
+	needsVerbose(t)
+	out := gateWriter{gate: make(chan struct{}), entered: make(chan struct{}, 10)}
+	v := New(&out)
+	v.V = true
+	v.Async(1, Block)
+	v.Println("blocked")
+	<-out.entered
+	v.Println("queued")
+	waiting := make(chan struct{})
+	go func() {
+		v.Println("waiting")
+		close(waiting)
+	}()
+	time.Sleep(10 * time.Millisecond) // let it block on the full queue
+
+	// A Verb waiting for room in its queue doesn't hold up the others.
+	var other strings.Builder
+	printed := make(chan struct{})
+	go func() {
+		w := New(&other)
+		w.Println("disabled")
+		w.V = true
+		w.Println("other")
+		close(printed)
+	}()
+	select {
+	case <-printed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("another Verb waited for the full queue")
+	}
+	if other.String() != "other\n" {
+		t.Errorf("other Verb wrote %q", other.String())
+	}
+
+	close(out.gate)
+	<-waiting
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := out.String(), "blocked\nqueued\nwaiting"; got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestVerb_AsyncError(t *testing.T) {
+	needsVerbose(t)
+	out := gateWriter{err: errors.New("disk full")}
+	v := New(&out)
+	v.V = true
+	v.Async(10, Block)
+	v.Println("lost")
+	if err := v.Close(); err == nil || err.Error() != "disk full" {
+		t.Errorf("Close() = %v, want disk full", err)
+	}
+}
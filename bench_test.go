@@ -0,0 +1,188 @@
+package verbose
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Disabled calls should cost an atomic load and a bool check, and nothing at all with -tags noverbose. Enabled calls shouldn't
+// allocate either, the Enabled benchmarks compare them with the code before call sites were cached. Compare with
+//
+//	go test -run x -bench . -benchmem
+//	go test -run x -bench . -benchmem -tags noverbose
+
+type benchData struct {
+	Name  string
+	Count int
+}
+
+func TestDisabledAllocs(t *testing.T) {
+	v := New(io.Discard)
+	n, s, d := 42, "state", benchData{"bench", 1}
+	for name, f := range map[string]func(){
+		"Print":    func() { v.Print(s, n) },
+		"Println":  func() { v.Println(s, n) },
+		"Printf":   func() { v.Printf("%s %d\n", s, n) },
+		"Printj":   func() { v.Printj(&d) }, // a struct value is boxed into an interface, a pointer is not
+		"Fprintln": func() { v.Fprintln(io.Discard, s, n) },
+		"Lazy":     func() { v.Println(s, Lazy(expensiveDump)) },
+		"PrintlnFunc": func() {
+			v.PrintlnFunc(func() []any { return []any{s, expensiveDump()} })
+		},
+	} {
+		if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+			t.Errorf("disabled %s allocated %v times, want 0", name, allocs)
+		}
+	}
+}
+
+func BenchmarkDisabledPrint(b *testing.B) {
+	v := New(io.Discard)
+	n := 42
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Print("state:", n)
+	}
+}
+
+func BenchmarkDisabledPrintln(b *testing.B) {
+	v := New(io.Discard)
+	n := 42
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Println("state:", n)
+	}
+}
+
+func BenchmarkDisabledPrintf(b *testing.B) {
+	v := New(io.Discard)
+	n := 42
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Printf("state: %d\n", n)
+	}
+}
+
+func BenchmarkDisabledPrintj(b *testing.B) {
+	v := New(io.Discard)
+	d := benchData{"bench", 1}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Printj(&d)
+	}
+}
+
+func BenchmarkDisabledFprintln(b *testing.B) {
+	v := New(io.Discard)
+	n := 42
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Fprintln(io.Discard, "state:", n)
+	}
+}
+
+// before is the enabled Print path as it was before output cached call sites and reused its buffers: runtime.Caller,
+// filepath.Base and time.Format on every call. The Enabled benchmarks run it beside the current code.
+func (v *Verb) before(msg string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !v.V {
+		return
+	}
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		file = "???"
+	} else {
+		file = filepath.Base(file)
+	}
+	var b strings.Builder
+	if v.PrintDate {
+		b.WriteString(time.Now().Format(v.Dformat))
+		b.WriteString(v.Delimeter)
+	}
+	if v.PrintLine {
+		fmt.Fprintf(&b, "%s:%d%s", file, line, v.Delimeter)
+	}
+	b.WriteString(msg)
+	io.WriteString(v.Out, b.String())
+}
+
+// benchEnabled runs print before and after the redesign, with and without the date and line prefix.
+func benchEnabled(b *testing.B, before, after func(v *Verb)) {
+	dformat := "%F %T"
+	for _, prefix := range []bool{false, true} {
+		v := New(io.Discard, dformat)
+		v.V = true
+		v.PrintDate, v.PrintLine = prefix, prefix
+		name := "plain"
+		if prefix {
+			name = "date+line"
+		}
+		b.Run(name+"/before", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				before(&v)
+			}
+		})
+		b.Run(name+"/after", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				after(&v)
+			}
+		})
+	}
+}
+
+func BenchmarkEnabledPrint(b *testing.B) {
+	n := 42
+	benchEnabled(b,
+		func(v *Verb) { v.before(fmt.Sprint("state: ", n)) },
+		func(v *Verb) { v.Print("state: ", n) })
+}
+
+func BenchmarkEnabledPrintln(b *testing.B) {
+	n := 42
+	benchEnabled(b,
+		func(v *Verb) { v.before(fmt.Sprintln("state:", n)) },
+		func(v *Verb) { v.Println("state:", n) })
+}
+
+func BenchmarkEnabledPrintf(b *testing.B) {
+	n := 42
+	benchEnabled(b,
+		func(v *Verb) { v.before(fmt.Sprintf("state: %d\n", n)) },
+		func(v *Verb) { v.Printf("state: %d\n", n) })
+}
+
+func expensiveDump() any {
+	return fmt.Sprintf("%v", make([]int, 64))
+}
+
+func BenchmarkDisabledEager(b *testing.B) {
+	v := New(io.Discard)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Println("state:", expensiveDump())
+	}
+}
+
+func BenchmarkDisabledLazy(b *testing.B) {
+	v := New(io.Discard)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Println("state:", Lazy(expensiveDump))
+	}
+}
+
+func BenchmarkDisabledPrintlnFunc(b *testing.B) {
+	v := New(io.Discard)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.PrintlnFunc(func() []any { return []any{"state:", expensiveDump()} })
+	}
+}
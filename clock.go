@@ -0,0 +1,75 @@
+package verbose
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells a Verb the time to print with each message, and the spinner how long to wait between frames. Tests
+// set a StepClock to get the same output every run.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// SystemClock is the real clock, used when a Verb or Spinner has none.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time        { return time.Now() }
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// now returns the time from the Verb's Clock, or the system time when it has none, in the Verb's Location if it has
+// one.
+func (v *Verb) now() time.Time {
+	t := time.Now()
+	if v.Clock != nil {
+		t = v.Clock.Now()
+	}
+	if v.Location != nil {
+		t = t.In(v.Location)
+	}
+	return t
+}
+
+// StepClock is a fake Clock for tests. Each call to Now returns its time and moves it on by the step, and Sleep moves
+// it on by the time slept without waiting.
+type StepClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// NewStepClock returns a StepClock starting at start that moves step per call to Now. A zero step stops it.
+func NewStepClock(start time.Time, step time.Duration) *StepClock {
+	return &StepClock{now: start, step: step}
+}
+
+// Now returns the clock's time and advances it by the step.
+func (c *StepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := c.now
+	c.now = c.now.Add(c.step)
+	return t
+}
+
+// Sleep advances the clock by d and returns at once.
+func (c *StepClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// Advance moves the clock on by d.
+func (c *StepClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// Set sets the clock's time.
+func (c *StepClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
@@ -0,0 +1,53 @@
+package verbose
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStepClock(t *testing.T) {
+	start := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	c := NewStepClock(start, time.Second)
+	if !c.Now().Equal(start) || !c.Now().Equal(start.Add(time.Second)) {
+		t.Error("Now doesn't step")
+	}
+	begin := time.Now()
+	c.Sleep(time.Hour)
+	if time.Since(begin) > time.Second {
+		t.Error("Sleep waited")
+	}
+	if got := c.Now(); !got.Equal(start.Add(time.Hour + 2*time.Second)) {
+		t.Errorf("after Sleep Now() = %v", got)
+	}
+	c.Set(start)
+	if !c.Now().Equal(start) {
+		t.Error("Set didn't set the time")
+	}
+}
+
+func TestVerb_Clock(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.V = true
+	v.PrintDate = true
+	v.Dformat = "15:04:05"
+	v.Delimeter = "|"
+	v.Clock = NewStepClock(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC), time.Second)
+	var times []time.Time
+	defer v.AddHook(func(e Entry) { times = append(times, e.Time) })()
+	v.Println("one")
+	v.Fprintf(&buf, "two\n")
+	v.ErrOut(errors.New("boom"), "three")
+	want := "07:08:09|one\n07:08:10|two\n07:08:11|error: three -- boom"
+	if got := buf.String(); len(got) < len(want) || got[:len(want)] != want {
+		t.Errorf("got %q, want it to start with %q", got, want)
+	}
+	v.PrintDate = false
+	v.Printj(1)
+	if len(times) != 4 || times[3].Format("15:04:05") != "07:08:12" {
+		t.Errorf("entry times %v", times)
+	}
+}
@@ -1,3 +1,6 @@
+//go:build ignore
+
+// Run this example with go run cmd/spintest.go.
 package main
 
 import (
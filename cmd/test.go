@@ -1,3 +1,6 @@
+//go:build ignore
+
+// Run this example with go run cmd/test.go.
 package main
 
 import (
@@ -90,10 +93,9 @@ func main() {
 	testWriter(verb.V)
 	fmt.Println("Done. If this is the only line, try", filepath.Base(os.Args[0]), "-v -s \"Some Text\"")
 	fmt.Println("Spinner")
-	quit := make(chan bool)
-	go z.Spin(quit)
+	go z.Spin()
 	time.Sleep(5 * time.Second)
-	quit <- true
+	z.Quit <- true
 	fmt.Println("ERRORS")
 	err := fmt.Errorf("This is an error")
 	z.V = false
@@ -0,0 +1,96 @@
+// timefmt converts time formats between date(1) strftime strings and Go layouts, and shows what each prints now.
+//
+//	timefmt "%F %T.%3N"
+//	timefmt -g "Mon Jan _2 15:04:05 2006"
+//	timefmt -u -t 2021-01-03T09:05:07Z "%s" "%a %e %b"
+//
+// It exits with status 1 if a format has an unknown directive or can't be converted.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rmasci/verbose"
+	"github.com/spf13/pflag"
+)
+
+func main() {
+	var goLayout, utc, help bool
+	var at string
+	pflag.BoolVarP(&goLayout, "go", "g", false, "The formats are Go layouts, converted to strftime.")
+	pflag.BoolVarP(&utc, "utc", "u", false, "Show the preview in UTC instead of the local zone.")
+	pflag.StringVarP(&at, "time", "t", "", "Preview this RFC 3339 time instead of now.")
+	pflag.BoolVarP(&help, "help", "h", false, "Help")
+	pflag.Parse()
+	if help || pflag.NArg() == 0 {
+		fmt.Printf("Usage: %s [flags] FORMAT...\n", filepath.Base(os.Args[0]))
+		pflag.PrintDefaults()
+		if !help {
+			os.Exit(2)
+		}
+		os.Exit(0)
+	}
+
+	now := time.Now()
+	if at != "" {
+		t, err := time.Parse(time.RFC3339Nano, at)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "timefmt: --time:", err)
+			os.Exit(2)
+		}
+		now = t
+	}
+	if utc {
+		now = now.UTC()
+	}
+
+	failed := false
+	for i, format := range pflag.Args() {
+		if i > 0 {
+			fmt.Println()
+		}
+		var ok bool
+		if goLayout {
+			ok = fromGo(format, now)
+		} else {
+			ok = fromStrftime(format, now)
+		}
+		failed = failed || !ok
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// fromStrftime shows format as a Go layout and formats now with it.
+func fromStrftime(format string, now time.Time) bool {
+	fmt.Printf("strftime  %s\n", format)
+	if _, err := verbose.CompileTime(format); err != nil {
+		fmt.Printf("error     %v\n", err)
+		return false
+	}
+	layout, err := verbose.StrftimeToGoLayout(format)
+	if err != nil {
+		fmt.Printf("go        none: %v\n", err)
+	} else {
+		fmt.Printf("go        %s\n", layout)
+	}
+	fmt.Printf("now       %s\n", verbose.Strftime(format, now))
+	return err == nil
+}
+
+// fromGo shows layout as a strftime format and formats now with it.
+func fromGo(layout string, now time.Time) bool {
+	fmt.Printf("go        %s\n", layout)
+	format, err := verbose.GoLayoutToStrftime(layout)
+	if err != nil {
+		fmt.Printf("strftime  none: %v\n", err)
+	} else {
+		fmt.Printf("strftime  %s\n", format)
+	}
+	fmt.Printf("now       %s\n", now.Format(layout))
+	return err == nil
+}
@@ -0,0 +1,93 @@
+// verbctl talks to a process that called verb.ListenControl, so you can change its verbose settings while it runs.
+//
+//	verbctl -s /run/mydaemon/verbose.sock set v on
+//	verbctl -s /run/mydaemon/verbose.sock get
+//
+// With no command verbctl reads commands from stdin, one per line.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rmasci/verbose"
+	"github.com/spf13/pflag"
+)
+
+func main() {
+	var sock string
+	var help bool
+	pflag.StringVarP(&sock, "socket", "s", os.Getenv("VERBOSE_SOCKET"), "Control socket of the process. Defaults to $VERBOSE_SOCKET.")
+	pflag.BoolVarP(&help, "help", "h", false, "Help")
+	pflag.Parse()
+	if help || sock == "" {
+		fmt.Printf("Usage: %s -s SOCKET [COMMAND]\n", filepath.Base(os.Args[0]))
+		pflag.PrintDefaults()
+		fmt.Printf("\nCommands:\n%s\n", verbose.ControlHelp)
+		if sock == "" && !help {
+			os.Exit(2)
+		}
+		os.Exit(0)
+	}
+
+	c, err := net.Dial("unix", sock)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "verbctl:", err)
+		os.Exit(1)
+	}
+	defer c.Close()
+	r := bufio.NewReader(c)
+
+	if pflag.NArg() > 0 {
+		if !send(c, r, strings.Join(pflag.Args(), " ")) {
+			os.Exit(1)
+		}
+		return
+	}
+	failed := false
+	in := bufio.NewScanner(os.Stdin)
+	for in.Scan() {
+		line := strings.TrimSpace(in.Text())
+		if line == "" {
+			continue
+		}
+		if !send(c, r, line) {
+			failed = true
+		}
+		if line == "quit" {
+			break
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// send writes one command and copies the reply to stdout, errors to stderr. It returns false if the command failed.
+func send(w io.Writer, r *bufio.Reader, cmd string) bool {
+	if _, err := fmt.Fprintln(w, cmd); err != nil {
+		fmt.Fprintln(os.Stderr, "verbctl:", err)
+		return false
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "verbctl: connection closed:", err)
+			return false
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "ok":
+			return true
+		case strings.HasPrefix(line, "error:"):
+			fmt.Fprintln(os.Stderr, line)
+			return false
+		}
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rmasci/verbose"
+	"github.com/spf13/pflag"
+)
+
+// verbCallRe matches a line that could have written a verbose message.
+var verbCallRe = regexp.MustCompile(`\.(Print|Println|Printf|PrintlnFunc|PrintfFunc|Printj|Fprint|Fprintln|Fprintf|Err|ErrOut)\(`)
+
+// sourceFile is a Go file from the source tree, read on first use.
+type sourceFile struct {
+	path    string
+	modTime time.Time
+	lines   []string
+	warned  bool
+}
+
+// sourceTree finds Go files by the base names verbose prints.
+type sourceTree struct {
+	byName map[string][]*sourceFile
+}
+
+// loadSourceTree indexes every .go file under root, skipping hidden directories and testdata.
+func loadSourceTree(root string) (*sourceTree, error) {
+	t := &sourceTree{byName: map[string][]*sourceFile{}}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		t.byName[name] = append(t.byName[name], &sourceFile{path: path, modTime: info.ModTime()})
+		return nil
+	})
+	return t, err
+}
+
+func (sf *sourceFile) load() error {
+	if sf.lines != nil {
+		return nil
+	}
+	f, err := os.Open(sf.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	sf.lines = []string{}
+	for sc.Scan() {
+		sf.lines = append(sf.lines, sc.Text())
+	}
+	return sc.Err()
+}
+
+// resolve returns the file a call site refers to. When several files share the base name, the one with a verbose
+// call on that line is preferred. others is the number of other candidates.
+func (t *sourceTree) resolve(name string, line int) (sf *sourceFile, others int) {
+	cands := t.byName[name]
+	if len(cands) == 0 {
+		return nil, 0
+	}
+	for _, c := range cands {
+		if c.load() == nil && line >= 1 && line <= len(c.lines) && verbCallRe.MatchString(c.lines[line-1]) {
+			return c, len(cands) - 1
+		}
+	}
+	return cands[0], len(cands) - 1
+}
+
+// annotate prints each message followed by the source lines that wrote it.
+func annotate(args []string) error {
+	var o options
+	var src string
+	var context int
+	fs := pflag.NewFlagSet("verbview annotate", pflag.ExitOnError)
+	o.flags(fs)
+	fs.StringVar(&src, "src", ".", "Root of the source tree the log was written by.")
+	fs.IntVarP(&context, "context", "C", 0, "Source lines to show around each call site.")
+	fs.Parse(args)
+	if o.help {
+		usage(fs, "annotate ")
+		return nil
+	}
+	if err := o.prepare(); err != nil {
+		return err
+	}
+	tree, err := loadSourceTree(src)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return o.annotate(os.Stdin, tree, context)
+	}
+	for _, name := range fs.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = o.annotate(f, tree, context)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (o *options) annotate(r io.Reader, tree *sourceTree, context int) error {
+	lr := o.reader(r, "")
+	for {
+		rec, err := lr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !o.match(rec) {
+			continue
+		}
+		o.print(os.Stdout, rec)
+		if rec.File != "" {
+			o.printSource(os.Stdout, tree, rec, context)
+		}
+	}
+}
+
+// printSource shows the source of rec's call site, with a warning if the source looks different from when the log
+// was written.
+func (o *options) printSource(w io.Writer, tree *sourceTree, rec verbose.Record, context int) {
+	warn := func(format string, a ...any) {
+		fmt.Fprintln(w, o.paint(colorRed, "    ! "+fmt.Sprintf(format, a...)))
+	}
+	sf, others := tree.resolve(rec.File, rec.Line)
+	if sf == nil {
+		warn("%s not found in the source tree", rec.File)
+		return
+	}
+	if err := sf.load(); err != nil {
+		warn("%v", err)
+		return
+	}
+	if others > 0 {
+		warn("%d other files named %s, showing %s", others, rec.File, sf.path)
+	}
+	if rec.Line < 1 || rec.Line > len(sf.lines) {
+		warn("%s has %d lines, source has drifted since the log was written", sf.path, len(sf.lines))
+		return
+	}
+	if !sf.warned && !rec.Time.IsZero() && sf.modTime.After(rec.Time) {
+		sf.warned = true
+		warn("%s changed at %s, after the log was written", sf.path, sf.modTime.Format(time.DateTime))
+	}
+	if !verbCallRe.MatchString(sf.lines[rec.Line-1]) {
+		warn("line %d is not a verbose call, source has probably drifted", rec.Line)
+	}
+	from, to := max(rec.Line-context, 1), min(rec.Line+context, len(sf.lines))
+	for n := from; n <= to; n++ {
+		mark := " "
+		if n == rec.Line {
+			mark = ">"
+		}
+		fmt.Fprintf(w, "    %s %s\n", o.paint(colorDim, fmt.Sprintf("%s%5d", mark, n)), strings.TrimRight(sf.lines[n-1], " \t"))
+	}
+}
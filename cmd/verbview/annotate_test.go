@@ -0,0 +1,177 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rmasci/verbose"
+)
+
+// writeTree writes files, by slash separated path, under a temp directory dated modTime and returns the directory.
+func writeTree(t *testing.T, modTime time.Time, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, src := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+var written = time.Date(2024, 3, 5, 14, 0, 0, 0, time.Local)
+
+func testTree(t *testing.T) (string, *sourceTree) {
+	root := writeTree(t, written, map[string]string{
+		"main.go":          "package main\n\nfunc main() {\n\tverb.Println(\"start\")\n\tverb.PrintfFunc(\"%d\\n\", args)\n\trun()\n}\n",
+		"a/util.go":        "package a\n\nfunc A() {\n\tx := 1\n}\n",
+		"b/util.go":        "package b\n\nfunc B() {\n\tverb.Printj(x)\n}\n",
+		".git/skip.go":     "package skip\n",
+		"testdata/skip.go": "package skip\n",
+		"b/testdata/x.go":  "package x\n",
+		"README.md":        "not go\n",
+	})
+	tree, err := loadSourceTree(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root, tree
+}
+
+func TestLoadSourceTree(t *testing.T) {
+	_, tree := testTree(t)
+	var names []string
+	for name, files := range tree.byName {
+		names = append(names, name+"="+strings.Repeat("*", len(files)))
+	}
+	sort.Strings(names)
+	if got := strings.Join(names, " "); got != "main.go=* util.go=**" {
+		t.Errorf("indexed %s", got)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root, tree := testTree(t)
+	tests := []struct {
+		name   string
+		line   int
+		want   string
+		others int
+	}{
+		{"main.go", 4, "main.go", 0},
+		{"util.go", 4, "b/util.go", 1},
+		{"util.go", 99, "a/util.go", 1}, // no verbose call there in either, the first is taken
+		{"missing.go", 1, "", 0},
+	}
+	for _, tt := range tests {
+		sf, others := tree.resolve(tt.name, tt.line)
+		got := ""
+		if sf != nil {
+			got = filepath.ToSlash(strings.TrimPrefix(sf.path, root+string(filepath.Separator)))
+		}
+		if got != tt.want || others != tt.others {
+			t.Errorf("resolve(%q, %d) = %q, %d, want %q, %d", tt.name, tt.line, got, others, tt.want, tt.others)
+		}
+	}
+}
+
+func TestPrintSource(t *testing.T) {
+	root, tree := testTree(t)
+	mainGo := filepath.Join(root, "main.go")
+	after := written.Add(time.Hour)
+	tests := []struct {
+		name    string
+		rec     verbose.Record
+		context int
+		want    string
+	}{
+		{
+			name: "call site",
+			rec:  verbose.Record{File: "main.go", Line: 4, Time: after},
+			want: "    >    4 \tverb.Println(\"start\")\n",
+		},
+		{
+			name:    "context",
+			rec:     verbose.Record{File: "main.go", Line: 5, Time: after},
+			context: 2,
+			want: "         3 func main() {\n" +
+				"         4 \tverb.Println(\"start\")\n" +
+				"    >    5 \tverb.PrintfFunc(\"%d\\n\", args)\n" +
+				"         6 \trun()\n" +
+				"         7 }\n",
+		},
+		{
+			name:    "context at the ends",
+			rec:     verbose.Record{File: "main.go", Line: 4, Time: after},
+			context: 10,
+			want: "         1 package main\n" +
+				"         2 \n" +
+				"         3 func main() {\n" +
+				"    >    4 \tverb.Println(\"start\")\n" +
+				"         5 \tverb.PrintfFunc(\"%d\\n\", args)\n" +
+				"         6 \trun()\n" +
+				"         7 }\n",
+		},
+		{
+			name: "not a verbose call",
+			rec:  verbose.Record{File: "main.go", Line: 6, Time: after},
+			want: "    ! line 6 is not a verbose call, source has probably drifted\n" +
+				"    >    6 \trun()\n",
+		},
+		{
+			name: "past the end",
+			rec:  verbose.Record{File: "main.go", Line: 40, Time: after},
+			want: "    ! " + mainGo + " has 7 lines, source has drifted since the log was written\n",
+		},
+		{
+			name: "changed after the log",
+			rec:  verbose.Record{File: "main.go", Line: 4, Time: written.Add(-time.Hour)},
+			want: "    ! " + mainGo + " changed at " + written.Format(time.DateTime) + ", after the log was written\n" +
+				"    >    4 \tverb.Println(\"start\")\n",
+		},
+		{
+			name: "shared name",
+			rec:  verbose.Record{File: "util.go", Line: 4, Time: after},
+			want: "    ! 1 other files named util.go, showing " + filepath.Join(root, "b", "util.go") + "\n" +
+				"    >    4 \tverb.Printj(x)\n",
+		},
+		{
+			name: "missing",
+			rec:  verbose.Record{File: "gone.go", Line: 4, Time: after},
+			want: "    ! gone.go not found in the source tree\n",
+		},
+	}
+	var o options
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			o.printSource(&b, tree, tt.rec, tt.context)
+			if got := b.String(); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintSource_WarnsOnce(t *testing.T) {
+	_, tree := testTree(t)
+	var o options
+	var b strings.Builder
+	rec := verbose.Record{File: "main.go", Line: 4, Time: written.Add(-time.Hour)}
+	o.printSource(&b, tree, rec, 0)
+	o.printSource(&b, tree, rec, 0)
+	if n := strings.Count(b.String(), "after the log was written"); n != 1 {
+		t.Errorf("warned %d times about the change:\n%s", n, b.String())
+	}
+}
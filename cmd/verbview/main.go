@@ -0,0 +1,370 @@
+// verbview reads log files written by verbose and shows them filtered and coloured.
+//
+//	verbview -f "%F %T" --since 10m --at db.go -e 'Query' app.log
+//	verbview -F app.log
+//	verbview merge extract.log load.log=%Y-%m-%dT%H:%M:%S
+//	verbview annotate --src ./ customer.log
+//
+// Dates are read back with the format the program used: pass the date(1) style string given to verbose.New with
+// -f, or the Go layout in Dformat with -l. Messages that span several lines, like Printj output, are kept together.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rmasci/verbose"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// options are the flags shared by every verbview command.
+type options struct {
+	format    string
+	layout    string
+	strftime  *verbose.TimeLayout
+	noDate    bool
+	delimeter string
+	since     string
+	until     string
+	at        string
+	grep      string
+	color     string
+	follow    bool
+	help      bool
+
+	from, to time.Time
+	re       *regexp.Regexp
+	atFile   string
+	atLine   int
+	colored  bool
+}
+
+func (o *options) flags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.format, "format", "f", "", "Date format as given to verbose.New, e.g. \"%F %T\" or rfc3339.")
+	fs.StringVarP(&o.layout, "layout", "l", "2006-01-02 15:04:05", "Date format as a Go layout, the Verb's Dformat.")
+	fs.BoolVar(&o.noDate, "no-date", false, "The log has no dates.")
+	fs.StringVarP(&o.delimeter, "delimeter", "d", " ", "Delimiter between date, line number and message.")
+	fs.StringVar(&o.since, "since", "", "Only messages at or after this time. A date, or a duration like 10m for that long ago.")
+	fs.StringVar(&o.until, "until", "", "Only messages before this time. A date, or a duration like 10m for that long ago.")
+	fs.StringVar(&o.at, "at", "", "Only messages from this file, or file:line.")
+	fs.StringVarP(&o.grep, "grep", "e", "", "Only messages matching this regular expression.")
+	fs.StringVar(&o.color, "color", "auto", "Colour output: auto, always or never.")
+	fs.BoolVarP(&o.help, "help", "h", false, "Help")
+}
+
+// prepare checks the flags and works out the filters.
+func (o *options) prepare() error {
+	var err error
+	if o.noDate {
+		o.format, o.layout = "", ""
+	}
+	if o.format != "" {
+		o.layout, o.format = dateFormat(o.format)
+	}
+	if o.format != "" {
+		if o.strftime, err = verbose.CompileTime(o.format); err != nil {
+			return fmt.Errorf("--format: %w", err)
+		}
+	}
+	if o.from, err = o.parseWhen(o.since); err != nil {
+		return fmt.Errorf("--since: %w", err)
+	}
+	if o.to, err = o.parseWhen(o.until); err != nil {
+		return fmt.Errorf("--until: %w", err)
+	}
+	if o.grep != "" {
+		if o.re, err = regexp.Compile(o.grep); err != nil {
+			return fmt.Errorf("--grep: %w", err)
+		}
+	}
+	if o.at != "" {
+		o.atFile = o.at
+		if file, line, ok := strings.Cut(o.at, ":"); ok {
+			o.atFile = file
+			if o.atLine, err = strconv.Atoi(line); err != nil {
+				return fmt.Errorf("--at: bad line number %q", line)
+			}
+		}
+	}
+	switch o.color {
+	case "always":
+		o.colored = true
+	case "never":
+		o.colored = false
+	case "auto":
+		o.colored = term.IsTerminal(int(os.Stdout.Fd()))
+	default:
+		return fmt.Errorf("--color must be auto, always or never")
+	}
+	return nil
+}
+
+// dateFormat returns the Dformat and Strftime of a Verb made with verbose.New(w, format), so a log is read with what
+// that Verb printed: a bare %N, for one, is .000 there, not nanoseconds.
+func dateFormat(format string) (layout, strftime string) {
+	v := verbose.New(io.Discard, format)
+	return strings.TrimSpace(v.Dformat), v.Strftime
+}
+
+// parseWhen reads a --since or --until value.
+func (o *options) parseWhen(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if o.strftime != nil {
+		if t, err := o.strftime.ParseInLocation(s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	for _, l := range []string{time.RFC3339Nano, strings.TrimSpace(o.layout), "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if l == "" {
+			continue
+		}
+		if t, err := time.ParseInLocation(l, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("15:04:05", s, time.Local); err == nil {
+		y, m, d := time.Now().Date()
+		return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, time.Local), nil
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a time or duration", s)
+}
+
+// reader opens a LogReader on r with the date layout and delimiter from the flags.
+func (o *options) reader(r io.Reader, source string) *verbose.LogReader {
+	lr := verbose.NewLogReader(r, o.layout)
+	lr.Strftime = o.format
+	lr.Delimeter = o.delimeter
+	lr.Source = source
+	return lr
+}
+
+// match reports whether rec passes the filters.
+func (o *options) match(rec verbose.Record) bool {
+	if !o.from.IsZero() && (rec.Time.IsZero() || rec.Time.Before(o.from)) {
+		return false
+	}
+	if !o.to.IsZero() && (rec.Time.IsZero() || !rec.Time.Before(o.to)) {
+		return false
+	}
+	if o.atFile != "" && (rec.File != o.atFile || (o.atLine != 0 && rec.Line != o.atLine)) {
+		return false
+	}
+	if o.re != nil && !o.re.MatchString(rec.Msg) {
+		return false
+	}
+	return true
+}
+
+const (
+	colorReset  = "\033[0m"
+	colorDim    = "\033[2m"
+	colorCyan   = "\033[36m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+)
+
+// paint wraps s in an ANSI colour when colouring is on.
+func (o *options) paint(color, s string) string {
+	if !o.colored || s == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// date formats t the way the log did.
+func (o *options) date(t time.Time) string {
+	if o.strftime != nil {
+		return o.strftime.Format(t)
+	}
+	return t.Format(strings.TrimSpace(o.layout))
+}
+
+// print writes rec to w, with the date and call site coloured and continuation lines indented.
+func (o *options) print(w io.Writer, rec verbose.Record) {
+	var b strings.Builder
+	if rec.Source != "" {
+		b.WriteString(o.paint(colorDim, "["+rec.Source+"]"))
+		b.WriteByte(' ')
+	}
+	if !rec.Time.IsZero() {
+		b.WriteString(o.paint(colorCyan, o.date(rec.Time)))
+		b.WriteByte(' ')
+	}
+	if rec.File != "" {
+		b.WriteString(o.paint(colorYellow, fmt.Sprintf("%s:%d", rec.File, rec.Line)))
+		b.WriteByte(' ')
+	}
+	msg := rec.Msg
+	if strings.HasPrefix(msg, "error:") {
+		msg = o.paint(colorRed, msg)
+	}
+	b.WriteString(strings.ReplaceAll(msg, "\n", "\n    "))
+	fmt.Fprintln(w, b.String())
+}
+
+// followReader keeps reading a file as it grows, like tail -f. idle is called each time it catches up with the end.
+type followReader struct {
+	f      *os.File
+	idle   func()
+	caught bool
+}
+
+func (fr *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := fr.f.Read(p)
+		if n > 0 || err != io.EOF {
+			fr.caught = false
+			return n, err
+		}
+		if !fr.caught && fr.idle != nil {
+			fr.caught = true
+			fr.idle()
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+func usage(fs *pflag.FlagSet, cmd string) {
+	fmt.Printf("Usage: %s %s[flags] [FILE...]\n", filepath.Base(os.Args[0]), cmd)
+	fs.PrintDefaults()
+}
+
+func main() {
+	run := view
+	args := os.Args[1:]
+	if len(args) > 0 {
+		switch args[0] {
+		case "merge":
+			run, args = merge, args[1:]
+		case "annotate":
+			run, args = annotate, args[1:]
+		}
+	}
+	if err := run(args); err != nil {
+		fmt.Fprintln(os.Stderr, "verbview:", err)
+		os.Exit(1)
+	}
+}
+
+// view shows the files, or stdin, filtered.
+func view(args []string) error {
+	var o options
+	fs := pflag.NewFlagSet("verbview", pflag.ExitOnError)
+	o.flags(fs)
+	fs.BoolVarP(&o.follow, "follow", "F", false, "Keep reading as the file grows, like tail -f.")
+	fs.Parse(args)
+	if o.help {
+		usage(fs, "")
+		return nil
+	}
+	if err := o.prepare(); err != nil {
+		return err
+	}
+	files := fs.Args()
+	if o.follow && len(files) > 1 {
+		return fmt.Errorf("--follow takes a single file")
+	}
+	if len(files) == 0 {
+		return o.show(o.reader(os.Stdin, ""))
+	}
+	for _, name := range files {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		var r io.Reader = f
+		var lr *verbose.LogReader
+		if o.follow {
+			// Show the newest message once the file stops growing, not when the next one is written.
+			r = &followReader{f: f, idle: func() {
+				if rec, ok := lr.Flush(); ok && o.match(rec) {
+					o.print(os.Stdout, rec)
+				}
+			}}
+		}
+		lr = o.reader(r, "")
+		err = o.show(lr)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// show prints the records from lr that pass the filters.
+func (o *options) show(lr *verbose.LogReader) error {
+	for {
+		rec, err := lr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if o.match(rec) {
+			o.print(os.Stdout, rec)
+		}
+	}
+}
+
+// merge interleaves several logs by time, tagging each message with the log it came from.
+// A file can be given as FILE=FORMAT when its dates are in a different format from the others.
+func merge(args []string) error {
+	var o options
+	fs := pflag.NewFlagSet("verbview merge", pflag.ExitOnError)
+	o.flags(fs)
+	fs.Parse(args)
+	if o.help || fs.NArg() == 0 {
+		usage(fs, "merge ")
+		fmt.Println("\nA file given as FILE=FORMAT has its dates read with that date(1) style format or preset.")
+		return nil
+	}
+	if err := o.prepare(); err != nil {
+		return err
+	}
+	var readers []*verbose.LogReader
+	for _, arg := range fs.Args() {
+		name, format, custom := strings.Cut(arg, "=")
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		lr := o.reader(f, filepath.Base(name))
+		if custom {
+			lr.Layout, lr.Strftime = dateFormat(format)
+			if lr.Strftime != "" {
+				if _, err := verbose.CompileTime(lr.Strftime); err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+			}
+		}
+		readers = append(readers, lr)
+	}
+	m := verbose.NewLogMerger(readers...)
+	for {
+		rec, err := m.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if o.match(rec) {
+			o.print(os.Stdout, rec)
+		}
+	}
+}
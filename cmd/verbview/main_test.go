@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rmasci/verbose"
+)
+
+func TestParseWhen(t *testing.T) {
+	o := options{format: "%d/%m/%Y %H:%M", layout: "2006-01-02 15:04:05", color: "never"}
+	if err := o.prepare(); err != nil {
+		t.Fatal(err)
+	}
+	y, m, d := time.Now().Date()
+	tests := []struct {
+		s    string
+		want time.Time
+		err  bool
+	}{
+		{s: ""},
+		{s: "05/03/2024 14:07", want: time.Date(2024, 3, 5, 14, 7, 0, 0, time.Local)},
+		{s: "2024-03-05 14:07:09", want: time.Date(2024, 3, 5, 14, 7, 9, 0, time.Local)},
+		{s: "2024-03-05T14:07:09", want: time.Date(2024, 3, 5, 14, 7, 9, 0, time.Local)},
+		{s: "2024-03-05T14:07:09.5Z", want: time.Date(2024, 3, 5, 14, 7, 9, 5e8, time.UTC)},
+		{s: "2024-03-05", want: time.Date(2024, 3, 5, 0, 0, 0, 0, time.Local)},
+		{s: "14:07:09", want: time.Date(y, m, d, 14, 7, 9, 0, time.Local)},
+		{s: "yesterday", err: true},
+	}
+	for _, tt := range tests {
+		got, err := o.parseWhen(tt.s)
+		if (err != nil) != tt.err || !got.Equal(tt.want) {
+			t.Errorf("parseWhen(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+
+	got, err := o.parseWhen("10m")
+	if want := time.Now().Add(-10 * time.Minute); err != nil || want.Sub(got) < 0 || want.Sub(got) > time.Second {
+		t.Errorf("parseWhen(10m) = %v, %v, want about %v", got, err, want)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	at := time.Date(2024, 3, 5, 14, 7, 9, 0, time.Local)
+	rec := verbose.Record{Time: at, File: "db.go", Line: 42, Msg: "Query took 3ms"}
+	undated := verbose.Record{File: "db.go", Line: 42, Msg: "Query took 3ms"}
+	tests := []struct {
+		name  string
+		o     options
+		rec   verbose.Record
+		match bool
+	}{
+		{"no filters", options{}, rec, true},
+		{"no filters, no date", options{}, undated, true},
+		{"since", options{since: "2024-03-05 14:07:09"}, rec, true},
+		{"since later", options{since: "2024-03-05 14:07:10"}, rec, false},
+		{"since, no date", options{since: "2024-03-05 14:07:09"}, undated, false},
+		{"until", options{until: "2024-03-05 14:07:10"}, rec, true},
+		{"until is exclusive", options{until: "2024-03-05 14:07:09"}, rec, false},
+		{"at file", options{at: "db.go"}, rec, true},
+		{"at file and line", options{at: "db.go:42"}, rec, true},
+		{"at other line", options{at: "db.go:43"}, rec, false},
+		{"at other file", options{at: "main.go"}, rec, false},
+		{"grep", options{grep: `took \d+ms`}, rec, true},
+		{"grep no match", options{grep: `^took`}, rec, false},
+	}
+	for _, tt := range tests {
+		tt.o.layout, tt.o.color = "2006-01-02 15:04:05", "never"
+		if err := tt.o.prepare(); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := tt.o.match(tt.rec); got != tt.match {
+			t.Errorf("%s: match = %v, want %v", tt.name, got, tt.match)
+		}
+	}
+}
+
+func TestPrepare_Errors(t *testing.T) {
+	for _, o := range []options{
+		{format: "%Q"},
+		{since: "soon"},
+		{until: "later"},
+		{grep: "("},
+		{at: "db.go:x"},
+		{color: "sometimes"},
+	} {
+		if o.color == "" {
+			o.color = "never"
+		}
+		if err := o.prepare(); err == nil {
+			t.Errorf("prepare(%+v) didn't fail", o)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package verbose
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ControlHelp lists the commands understood by ListenControl.
+const ControlHelp = `get                     show the current settings
+set v on|off            turn verbose mode on or off
+set printdate on|off    print the date
+set printline on|off    print file and line number
+set dformat LAYOUT      set the date format using a Go layout
+set strftime FORMAT     set the date format using a date(1) format or a preset like rfc3339
+set location ZONE       print dates in ZONE, e.g. UTC or Europe/Paris, or Local for the host's zone
+set delimeter TEXT      set the delimiter
+set out stdout|stderr   write to stdout or stderr
+reopen                  reopen the output file, e.g. after logrotate moved it
+help                    show this help
+quit                    close the connection`
+
+// ListenControl listens on the Unix domain socket path for commands that change the Verb's settings, so an operator
+// can adjust a running process with cmd/verbctl or `nc -U path`. The protocol is line based: each command line gets
+// zero or more lines of output followed by "ok" or "error: <reason>". See ControlHelp for the commands.
+//
+// The socket has mode 0600. It is created in a new directory only the owner can enter and moved to path once its mode
+// is set, so other users can't connect in between whatever the umask. A stale socket left at path is removed first;
+// anything else there is an error.
+// Close the returned listener to stop; that removes the socket.
+func (v *Verb) ListenControl(path string) (io.Closer, error) {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		if c, err := net.Dial("unix", path); err == nil {
+			c.Close()
+			return nil, fmt.Errorf("verbose: %s is in use", path)
+		}
+		os.Remove(path)
+	}
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".verbose-control-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "sock")
+	l, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err = os.Chmod(tmp, 0600); err == nil {
+		// Link, unlike Rename, fails rather than replace something else at path.
+		if err = os.Link(tmp, path); errors.Is(err, fs.ErrExist) {
+			err = fmt.Errorf("verbose: %s exists and is not a socket", path)
+		}
+	}
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					return
+				}
+				continue
+			}
+			go v.serveControl(c)
+		}
+	}()
+	return controlListener{l, path}, nil
+}
+
+// controlListener removes the socket when it is closed, since the listener only knows the name it was created with.
+type controlListener struct {
+	net.Listener
+	path string
+}
+
+func (l controlListener) Close() error {
+	err := l.Listener.Close()
+	os.Remove(l.path)
+	return err
+}
+
+func (v *Verb) serveControl(c net.Conn) {
+	defer c.Close()
+	sc := bufio.NewScanner(c)
+	w := bufio.NewWriter(c)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if line == "quit" {
+			fmt.Fprintln(w, "ok")
+			w.Flush()
+			return
+		}
+		if err := v.control(w, line); err != nil {
+			fmt.Fprintln(w, "error:", err)
+		} else {
+			fmt.Fprintln(w, "ok")
+		}
+		if w.Flush() != nil {
+			return
+		}
+	}
+}
+
+// control runs a single command line, writing any output to w.
+func (v *Verb) control(w io.Writer, line string) error {
+	cmd, args, _ := strings.Cut(line, " ")
+	switch cmd {
+	case "help":
+		fmt.Fprintln(w, ControlHelp)
+		return nil
+	case "get":
+		s := v.Settings()
+		fmt.Fprintf(w, "v %v\nout %s\ndformat %s\ndelimeter %q\nprintdate %v\nprintline %v\n",
+			s.V, s.Out, s.Dformat, s.Delimeter, s.PrintDate, s.PrintLine)
+		if s.Strftime != "" {
+			fmt.Fprintf(w, "strftime %s\n", s.Strftime)
+		}
+		if s.Location != "" {
+			fmt.Fprintf(w, "location %s\n", s.Location)
+		}
+		return nil
+	case "reopen", "rotate":
+		return v.reopen()
+	case "set":
+	default:
+		return fmt.Errorf("unknown command %q, try help", cmd)
+	}
+
+	name, value, _ := strings.Cut(strings.TrimLeft(args, " "), " ")
+	var c SettingsChange
+	switch strings.ToLower(name) {
+	case "v", "printdate", "printline":
+		on, err := parseOnOff(value)
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(name) {
+		case "v":
+			c.V = &on
+		case "printdate":
+			c.PrintDate = &on
+		case "printline":
+			c.PrintLine = &on
+		}
+	case "dformat":
+		c.Dformat = &value
+	case "strftime":
+		c.Strftime = &value
+	case "location", "tz":
+		c.Location = &value
+	case "delimeter", "delimiter":
+		if s, err := strconv.Unquote(value); err == nil {
+			value = s
+		}
+		c.Delimeter = &value
+	case "out":
+		c.Out = &value
+	default:
+		return fmt.Errorf("unknown setting %q, try help", name)
+	}
+	return v.change(c, "control: "+line)
+}
+
+func parseOnOff(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "on", "true", "1", "yes":
+		return true, nil
+	case "off", "false", "0", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("want on or off, not %q", s)
+}
+
+// reopen closes the output file and opens it again by name for appending, so output follows a file that was rotated.
+func (v *Verb) reopen() error {
+	v.Flush()
+	mu.Lock()
+	f, ok := v.Out.(*os.File)
+	if !ok || f == os.Stdout || f == os.Stderr {
+		mu.Unlock()
+		return fmt.Errorf("output %s is not a file", describeWriter(v.Out))
+	}
+	nf, err := os.OpenFile(f.Name(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		mu.Unlock()
+		return err
+	}
+	v.Out = nf
+	v.logChange("reopened %s", nf.Name())
+	mu.Unlock()
+	// A write started before the switch may still be going to f.
+	outMu := v.writeLock()
+	outMu.Lock()
+	f.Close()
+	outMu.Unlock()
+	return nil
+}
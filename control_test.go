@@ -0,0 +1,141 @@
+package verbose
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// controlRoundTrip sends cmd and returns the output lines before the final status line, and the status.
+func controlRoundTrip(t *testing.T, c net.Conn, r *bufio.Reader, cmd string) ([]string, string) {
+	t.Helper()
+	if _, err := c.Write([]byte(cmd + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	var out []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("%s: %v", cmd, err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "ok" || strings.HasPrefix(line, "error:") {
+			return out, line
+		}
+		out = append(out, line)
+	}
+}
+
+func TestVerb_ListenControl(t *testing.T) {
+	needsVerbose(t)
+	dir, err := os.MkdirTemp("", "verbctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	logName := filepath.Join(dir, "verbose.log")
+	f, err := os.Create(logName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := New(f)
+	sock := filepath.Join(dir, "ctl.sock")
+	l, err := v.ListenControl(sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if _, err := v.ListenControl(sock); err == nil {
+		t.Error("second ListenControl on a live socket succeeded")
+	}
+
+	c, err := net.Dial("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	r := bufio.NewReader(c)
+
+	if _, status := controlRoundTrip(t, c, r, "set v on"); status != "ok" || !v.Enabled() {
+		t.Errorf("set v on: %s, Enabled() = %v", status, v.Enabled())
+	}
+	if _, status := controlRoundTrip(t, c, r, "set strftime %H:%M"); status != "ok" {
+		t.Errorf("set strftime: %s", status)
+	}
+	if _, status := controlRoundTrip(t, c, r, "set location UTC"); status != "ok" {
+		t.Errorf("set location: %s", status)
+	}
+	if _, status := controlRoundTrip(t, c, r, "set location Mars/Olympus_Mons"); !strings.HasPrefix(status, "error:") {
+		t.Errorf("set location to an unknown zone: %s", status)
+	}
+	out, status := controlRoundTrip(t, c, r, "get")
+	if got := strings.Join(out, "\n"); status != "ok" || !strings.Contains(got, "dformat 15:04") ||
+		!strings.Contains(got, "location UTC") {
+		t.Errorf("get = %q %s", out, status)
+	}
+	if _, status := controlRoundTrip(t, c, r, "set v maybe"); !strings.HasPrefix(status, "error:") {
+		t.Errorf("set v maybe: %s", status)
+	}
+	if _, status := controlRoundTrip(t, c, r, "frobnicate"); !strings.HasPrefix(status, "error:") {
+		t.Errorf("unknown command: %s", status)
+	}
+
+	os.Rename(logName, logName+".1")
+	if _, status := controlRoundTrip(t, c, r, "reopen"); status != "ok" {
+		t.Fatalf("reopen: %s", status)
+	}
+	v.Println("after rotate")
+	b, _ := os.ReadFile(logName)
+	if !strings.Contains(string(b), "after rotate") {
+		t.Errorf("new log file = %q", b)
+	}
+	if _, status := controlRoundTrip(t, c, r, "quit"); status != "ok" {
+		t.Errorf("quit: %s", status)
+	}
+}
+
+func TestVerb_ListenControl_Socket(t *testing.T) {
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "ctl.sock")
+	v := New(io.Discard)
+	l, err := v.ListenControl(sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&os.ModeSocket == 0 || fi.Mode().Perm() != 0600 {
+		t.Errorf("socket mode %v, want a socket with 0600", fi.Mode())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("left behind %v", entries)
+	}
+	l.Close()
+	if _, err := os.Lstat(sock); !os.IsNotExist(err) {
+		t.Errorf("socket still there after Close: %v", err)
+	}
+}
+
+func TestVerb_ListenControl_NotASocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "important.conf")
+	if err := os.WriteFile(path, []byte("keep me\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	v := New(io.Discard)
+	if l, err := v.ListenControl(path); err == nil {
+		l.Close()
+		t.Fatal("ListenControl replaced a regular file")
+	}
+	if b, err := os.ReadFile(path); err != nil || string(b) != "keep me\n" {
+		t.Errorf("file now %q, %v", b, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("left behind %v", entries)
+	}
+}
@@ -0,0 +1,66 @@
+package verbose
+
+import (
+	"flag"
+	"strings"
+	"sync/atomic"
+)
+
+// TB is the part of testing.TB that ForTest uses, so this package doesn't import testing.
+type TB interface {
+	Helper()
+	Log(args ...any)
+	Cleanup(func())
+}
+
+// ForTest returns a Verb that prints through t.Log, so messages show up with the test that printed them. It is on
+// when the tests run with go test -v. PrintLine is set, since t.Log reports the line inside this package.
+//
+//	func TestQuery(t *testing.T) {
+//		verb := verbose.ForTest(t)
+//		...
+func ForTest(t TB) *Verb {
+	v := New(nil)
+	v.ForTest(t)
+	return &v
+}
+
+// ForTest sends an existing Verb, such as a package's shared one, to t.Log until the test ends, as the function
+// ForTest does. The previous output, V and PrintLine are put back in t.Cleanup.
+//
+//	verb.ForTest(t)
+func (v *Verb) ForTest(t TB) {
+	t.Helper()
+	v.Flush()
+	mu.Lock()
+	out, line := v.Out, v.PrintLine
+	on := atomic.LoadInt32(&v.on)
+	v.Out = tbWriter{t}
+	v.SetV(testVerbose())
+	v.PrintLine = true
+	mu.Unlock()
+	t.Cleanup(func() {
+		v.Flush()
+		mu.Lock()
+		v.Out, v.PrintLine = out, line
+		atomic.StoreInt32(&v.on, on)
+		mu.Unlock()
+	})
+}
+
+// testVerbose reports whether go test was given -v.
+func testVerbose() bool {
+	f := flag.Lookup("test.v")
+	return f != nil && f.Value.String() != "false"
+}
+
+// tbWriter writes each message to t.Log.
+type tbWriter struct {
+	t TB
+}
+
+func (w tbWriter) Write(p []byte) (int, error) {
+	w.t.Helper()
+	w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
@@ -0,0 +1,53 @@
+package verbose
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakeTB records what a Verb logs through it and runs its cleanups when done is called.
+type fakeTB struct {
+	logs     []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper()           {}
+func (f *fakeTB) Log(args ...any)   { f.logs = append(f.logs, fmt.Sprint(args...)) }
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+
+func (f *fakeTB) done() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestForTest(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.Delimeter = "|"
+	tb := &fakeTB{}
+	v.ForTest(tb)
+	if v.Enabled() != testing.Verbose() {
+		t.Errorf("Enabled() = %v under go test -v=%v", v.Enabled(), testing.Verbose())
+	}
+	v.SetV(true)
+	v.Println("in test")
+	tb.done()
+	if len(tb.logs) != 1 || !strings.HasPrefix(tb.logs[0], "fortest_test.go:") || !strings.HasSuffix(tb.logs[0], "|in test") {
+		t.Errorf("logged %q", tb.logs)
+	}
+	if v.Out != &buf || v.Enabled() || v.PrintLine {
+		t.Errorf("not restored: Out %T, Enabled() %v, PrintLine %v", v.Out, v.Enabled(), v.PrintLine)
+	}
+	v.V = true
+	v.Println("after")
+	if buf.String() != "after\n" {
+		t.Errorf("after cleanup got %q", buf.String())
+	}
+
+	// A real test, to see where the messages show up with go test -v.
+	ForTest(t).Println("logged with", t.Name())
+}
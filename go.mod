@@ -1,17 +1,10 @@
 module github.com/rmasci/verbose
 
-go 1.22
+go 1.22.0
 
 require (
-	github.com/artyom/spinner v0.0.1
 	github.com/spf13/pflag v1.0.5
+	golang.org/x/term v0.1.0
 )
 
-require (
-	github.com/briandowns/spinner v1.23.0 // indirect
-	github.com/fatih/color v1.7.0 // indirect
-	github.com/mattn/go-colorable v0.1.2 // indirect
-	github.com/mattn/go-isatty v0.0.8 // indirect
-	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
-	golang.org/x/term v0.1.0 // indirect
-)
+require golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
@@ -0,0 +1,249 @@
+package verbose
+
+import (
+	"fmt"
+	"strings"
+)
+
+// goLayouts are the date(1) directives, with their flag and colons, that have a Go layout equivalent.
+var goLayouts = map[string]string{
+	"Y": "2006", "y": "06", "m": "01", "-m": "1", "d": "02", "-d": "2", "e": "_2", "j": "002",
+	"B": "January", "b": "Jan", "h": "Jan", "A": "Monday", "a": "Mon",
+	"H": "15", "I": "03", "-I": "3", "M": "04", "-M": "4", "S": "05", "-S": "5",
+	"p": "PM", "P": "pm", "Z": "MST", "z": "-0700", ":z": "-07:00", "::z": "-07:00:00",
+	"D": "01/02/06", "x": "01/02/06", "F": "2006-01-02", "T": "15:04:05", "X": "15:04:05", "R": "15:04",
+	"r": "03:04:05 PM", "n": "\n", "t": "\t", "%": "%",
+}
+
+// legacyLayouts are what TimeFormatStr has always made of directives whose Go layout prints something else.
+var legacyLayouts = map[string]string{"%P": "PM", "%N": ".000"}
+
+// StrftimeToGoLayout is TimeFormatStr that says what it couldn't translate: a directive Strftime doesn't know, one
+// with no Go layout equivalent such as %s, %k or a %N not after a dot or comma, a format ending in a lone %, and
+// literal text a Go layout would read as part of the date, like the 1 in "%Y 1". The layout returned with an error
+// leaves those out.
+//
+// Unlike TimeFormatStr it keeps the meaning Strftime gives %P, the lower case "pm".
+//
+//	layout, err := verbose.StrftimeToGoLayout("%F %T.%3N") // "2006-01-02 15:04:05.000"
+func StrftimeToGoLayout(format string) (string, error) {
+	return goLayout(format, false)
+}
+
+// goLayout translates format, skipping what it can't translate, and returns the first problem with it. legacy
+// translates %P and a bare %N the way TimeFormatStr always has, to PM and .000.
+func goLayout(format string, legacy bool) (string, error) {
+	var b strings.Builder
+	var first error
+	fail := func(err error) {
+		if first == nil {
+			first = err
+		}
+	}
+	lit := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		if err := checkLiteral(format[lit:i], format); err != nil {
+			fail(err)
+		}
+		b.WriteString(format[lit:i])
+		start := i
+		i++
+		for i < len(format) && strings.IndexByte("-_0^#:", format[i]) >= 0 {
+			i++
+		}
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		lit = i + 1
+		if i >= len(format) {
+			fail(fmt.Errorf("verbose: format %q ends in an incomplete directive %q", format, format[start:]))
+			break
+		}
+		raw := format[start : i+1]
+		if format[i] == 'N' {
+			if s, ok := fracLayout(raw, b.String()); ok {
+				b.WriteString(s)
+				continue
+			}
+		}
+		if legacy {
+			if s, ok := legacyLayouts[raw]; ok {
+				b.WriteString(s)
+				continue
+			}
+		}
+		if s, ok := goLayouts[raw[1:]]; ok {
+			b.WriteString(s)
+			continue
+		}
+		if _, err := CompileTime(raw); err != nil {
+			fail(fmt.Errorf("verbose: unknown directive %q in format %q", raw, format))
+		} else {
+			fail(fmt.Errorf("verbose: directive %q in format %q has no Go layout equivalent", raw, format))
+		}
+	}
+	if lit < len(format) {
+		if err := checkLiteral(format[lit:], format); err != nil {
+			fail(err)
+		}
+		b.WriteString(format[lit:])
+	}
+	return b.String(), first
+}
+
+// fracLayout translates %N, %3N and the like after a dot or comma, to that many zeros, 9 without a width.
+func fracLayout(raw, before string) (string, bool) {
+	width := 9
+	if len(raw) > 2 {
+		width = 0
+		for _, c := range raw[1 : len(raw)-1] {
+			if c < '0' || c > '9' {
+				return "", false
+			}
+			width = width*10 + int(c-'0')
+		}
+	}
+	if width < 1 || width > 9 {
+		return "", false
+	}
+	if strings.HasSuffix(before, ".") || strings.HasSuffix(before, ",") {
+		return strings.Repeat("0", width), true
+	}
+	return "", false
+}
+
+// checkLiteral returns an error if text from format would be read as a layout element.
+func checkLiteral(text, format string) error {
+	for text != "" {
+		_, elem, rest, _ := nextLayoutElem(text)
+		if elem != "" {
+			return fmt.Errorf("verbose: text %q in format %q would be read as %q in a Go layout", text, format, elem)
+		}
+		text = rest
+	}
+	return nil
+}
+
+// GoLayoutToStrftime turns a Go layout into a date(1) format, the other way from TimeFormatStr. Layout elements with
+// no directive to match, like the Z07:00 zone that prints Z for UTC or the .999 fraction that drops trailing zeros,
+// are an error.
+//
+//	format, err := verbose.GoLayoutToStrftime(time.RFC1123) // "%a, %d %b %Y %H:%M:%S %Z"
+func GoLayoutToStrftime(layout string) (string, error) {
+	var b strings.Builder
+	for rest := layout; rest != ""; {
+		prefix, elem, after, directive := nextLayoutElem(rest)
+		b.WriteString(strings.ReplaceAll(prefix, "%", "%%"))
+		if elem != "" && directive == "" {
+			return "", fmt.Errorf("verbose: %q in layout %q has no strftime equivalent", elem, layout)
+		}
+		b.WriteString(directive)
+		rest = after
+	}
+	return b.String(), nil
+}
+
+// nextLayoutElem finds the first element of a Go layout the way the time package does. It returns the text before it,
+// the element, the text after it and the matching directive, which is empty if there is none. elem is empty if
+// layout has no more elements.
+func nextLayoutElem(layout string) (prefix, elem, rest, directive string) {
+	at := func(i int, s string) bool {
+		return strings.HasPrefix(layout[i:], s)
+	}
+	found := func(i int, e, d string) (string, string, string, string) {
+		return layout[:i], e, layout[i+len(e):], d
+	}
+	for i := 0; i < len(layout); i++ {
+		switch layout[i] {
+		case 'J':
+			if at(i, "January") {
+				return found(i, "January", "%B")
+			}
+			if at(i, "Jan") {
+				return found(i, "Jan", "%b")
+			}
+		case 'M':
+			if at(i, "Monday") {
+				return found(i, "Monday", "%A")
+			}
+			if at(i, "Mon") {
+				return found(i, "Mon", "%a")
+			}
+			if at(i, "MST") {
+				return found(i, "MST", "%Z")
+			}
+		case '0':
+			if i+1 < len(layout) && layout[i+1] >= '1' && layout[i+1] <= '6' {
+				return found(i, layout[i:i+2], [...]string{"%m", "%d", "%I", "%M", "%S", "%y"}[layout[i+1]-'1'])
+			}
+			if at(i, "002") {
+				return found(i, "002", "%j")
+			}
+		case '1':
+			if at(i, "15") {
+				return found(i, "15", "%H")
+			}
+			return found(i, "1", "%-m")
+		case '2':
+			if at(i, "2006") {
+				return found(i, "2006", "%Y")
+			}
+			return found(i, "2", "%-d")
+		case '_':
+			if at(i, "_2") {
+				if at(i, "_2006") {
+					// The time package reads this as an underscore and the year.
+					return layout[:i+1], "2006", layout[i+5:], "%Y"
+				}
+				return found(i, "_2", "%e")
+			}
+			if at(i, "__2") {
+				return found(i, "__2", "%_j")
+			}
+		case '3':
+			return found(i, "3", "%-I")
+		case '4':
+			return found(i, "4", "%-M")
+		case '5':
+			return found(i, "5", "%-S")
+		case 'P':
+			if at(i, "PM") {
+				return found(i, "PM", "%p")
+			}
+		case 'p':
+			if at(i, "pm") {
+				return found(i, "pm", "%P")
+			}
+		case '-', 'Z':
+			for _, z := range []struct{ e, d string }{
+				{"070000", ""}, {"07:00:00", "%::z"}, {"0700", "%z"}, {"07:00", "%:z"}, {"07", ""},
+			} {
+				if at(i+1, z.e) {
+					if layout[i] == 'Z' {
+						return found(i, "Z"+z.e, "")
+					}
+					return found(i, "-"+z.e, z.d)
+				}
+			}
+		case '.', ',':
+			if i+1 < len(layout) && (layout[i+1] == '0' || layout[i+1] == '9') {
+				ch := layout[i+1]
+				j := i + 1
+				for j < len(layout) && layout[j] == ch {
+					j++
+				}
+				if j < len(layout) && layout[j] >= '0' && layout[j] <= '9' {
+					break
+				}
+				if ch == '9' || j-i-1 > 9 {
+					return found(i, layout[i:j], "")
+				}
+				return found(i, layout[i:j], fmt.Sprintf("%c%%%dN", layout[i], j-i-1))
+			}
+		}
+	}
+	return layout, "", "", ""
+}
@@ -0,0 +1,111 @@
+package verbose
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStrftimeToGoLayout(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+		err    string
+	}{
+		{format: "%A %B %d %Y, %I:%M:%S %P %Z", want: "Monday January 02 2006, 03:04:05 pm MST"},
+		{format: "%F %T.%3N %z", want: "2006-01-02 15:04:05.000 -0700"},
+		{format: "%Y-%m-%dT%H:%M:%S,%N%:z", want: "2006-01-02T15:04:05,000000000-07:00"},
+		{format: "%-m/%-d/%y %-I:%-M:%-S %p", want: "1/2/06 3:4:5 PM"},
+		{format: "%e %b %R %::z", want: "_2 Jan 15:04 -07:00:00"},
+		{format: "%j %r", want: "002 03:04:05 PM"},
+		{format: "at %T, %%", want: "at 15:04:05, %"},
+		{format: "%s", err: `directive "%s" in format "%s" has no Go layout equivalent`},
+		{format: "%F %k", err: `directive "%k" in format "%F %k" has no Go layout equivalent`},
+		{format: "%3N", err: `directive "%3N" in format "%3N" has no Go layout equivalent`},
+		{format: "%T%N", err: `directive "%N" in format "%T%N" has no Go layout equivalent`},
+		{format: "%Q", err: `unknown directive "%Q" in format "%Q"`},
+		{format: "%T %", err: `format "%T %" ends in an incomplete directive "%"`},
+		{format: "%T %-", err: `format "%T %-" ends in an incomplete directive "%-"`},
+		{format: "at %Y 1", err: `text " 1" in format "at %Y 1" would be read as "1" in a Go layout`},
+		{format: "Mon %T", err: `text "Mon " in format "Mon %T" would be read as "Mon" in a Go layout`},
+	}
+	for _, tt := range tests {
+		got, err := StrftimeToGoLayout(tt.format)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("StrftimeToGoLayout(%q) error = %v, want %q", tt.format, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("StrftimeToGoLayout(%q) = %q, %v, want %q", tt.format, got, err, tt.want)
+		}
+	}
+}
+
+func TestTimeFormatStr_Lenient(t *testing.T) {
+	for format, want := range map[string]string{
+		"%T %":     "15:04:05",
+		"%F %k%Q ": "2006-01-02",
+		"%T.%N":    "15:04:05.000000000",
+		"%H%N":     "15.000",
+		"%I %P":    "03 PM",
+	} {
+		if got := TimeFormatStr(format); got != want {
+			t.Errorf("TimeFormatStr(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestGoLayoutToStrftime(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   string
+		err    string
+	}{
+		{layout: time.ANSIC, want: "%a %b %e %H:%M:%S %Y"},
+		{layout: time.RFC1123, want: "%a, %d %b %Y %H:%M:%S %Z"},
+		{layout: time.RFC1123Z, want: "%a, %d %b %Y %H:%M:%S %z"},
+		{layout: time.Kitchen, want: "%-I:%M%p"},
+		{layout: time.StampMicro, want: "%b %e %H:%M:%S.%6N"},
+		{layout: "2006-01-02T15:04:05,000-07:00:00", want: "%Y-%m-%dT%H:%M:%S,%3N%::z"},
+		{layout: "Monday January 2 _2 002 __2 1 3 4 5 pm", want: "%A %B %-d %e %j %_j %-m %-I %-M %-S %P"},
+		{layout: "x_2006 at 100%", want: "x_%Y at %-m00%%"},
+		{layout: "15:04:05.0001x", want: "%H:%M:%S.00%mx"}, // .0 isn't a fraction before a digit
+		{layout: time.RFC3339, err: `"Z07:00" in layout`},
+		{layout: time.RFC3339Nano, err: `".999999999" in layout`},
+		{layout: "15:04:05.999", err: `".999" in layout`},
+		{layout: "2006 -07", err: `"-07" in layout`},
+	}
+	for _, tt := range tests {
+		got, err := GoLayoutToStrftime(tt.layout)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("GoLayoutToStrftime(%q) error = %v, want %q", tt.layout, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("GoLayoutToStrftime(%q) = %q, %v, want %q", tt.layout, got, err, tt.want)
+		}
+	}
+}
+
+func TestGoLayoutToStrftime_SameOutput(t *testing.T) {
+	when := time.Date(2021, 1, 3, 9, 5, 7, 123456789, time.FixedZone("NST", -(3*3600+30*60)))
+	for _, layout := range []string{time.ANSIC, time.RFC822Z, time.RFC1123, time.Kitchen, time.StampNano, time.DateTime} {
+		format, err := GoLayoutToStrftime(layout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := Strftime(format, when), when.Format(layout); got != want {
+			t.Errorf("%q as %q: %q, want %q", layout, format, got, want)
+		}
+		back, err := StrftimeToGoLayout(format)
+		if err != nil {
+			t.Errorf("%q back to a layout: %v", format, err)
+		} else if got, want := when.Format(back), when.Format(layout); got != want {
+			t.Errorf("%q back as %q: %q, want %q", layout, back, got, want)
+		}
+	}
+}
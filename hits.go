@@ -0,0 +1,120 @@
+package verbose
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"text/tabwriter"
+)
+
+// HitCounter counts the calls made at every verbose call site, giving a map of which trace points a run went
+// through and how often, like a lightweight coverage report of the program flow.
+//
+//	verb.Hits = verbose.NewHitCounter()
+//	defer verb.Hits.Report()
+type HitCounter struct {
+	// ReportTo is where Report writes. Defaults to os.Stderr.
+	ReportTo io.Writer
+	// JSON makes Report write JSON instead of a table.
+	JSON bool
+
+	mu     sync.Mutex
+	counts map[uintptr]int64
+}
+
+// Hit is the number of times one call site ran.
+type Hit struct {
+	File  string `json:"file"`
+	Line  int    `json:"line"`
+	Func  string `json:"func"`
+	Count int64  `json:"count"`
+}
+
+// NewHitCounter returns an empty HitCounter.
+func NewHitCounter() *HitCounter {
+	return &HitCounter{counts: map[uintptr]int64{}}
+}
+
+// hit counts a call to one of the Verb's methods. It must be called directly from the exported method.
+func (v *Verb) hit() {
+	if v.Hits == nil {
+		return
+	}
+	var pc [1]uintptr
+	if runtime.Callers(3, pc[:]) == 1 {
+		v.Hits.add(pc[0])
+	}
+}
+
+func (h *HitCounter) add(pc uintptr) {
+	h.mu.Lock()
+	if h.counts == nil {
+		h.counts = map[uintptr]int64{}
+	}
+	h.counts[pc]++
+	h.mu.Unlock()
+}
+
+// Hits returns the call sites that ran, ordered by file and line.
+func (h *HitCounter) Hits() []Hit {
+	h.mu.Lock()
+	sites := map[Hit]int64{}
+	for pc, n := range h.counts {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		sites[Hit{File: frame.File, Line: frame.Line, Func: frame.Function}] += n
+	}
+	h.mu.Unlock()
+	hits := make([]Hit, 0, len(sites))
+	for site, n := range sites {
+		site.Count = n
+		hits = append(hits, site)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].File != hits[j].File {
+			return hits[i].File < hits[j].File
+		}
+		return hits[i].Line < hits[j].Line
+	})
+	return hits
+}
+
+// Reset forgets all counts.
+func (h *HitCounter) Reset() {
+	h.mu.Lock()
+	h.counts = map[uintptr]int64{}
+	h.mu.Unlock()
+}
+
+// WriteReport writes the hits to w as a table of call site, function and count.
+func (h *HitCounter) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CALL SITE\tFUNCTION\tCOUNT")
+	for _, hit := range h.Hits() {
+		fmt.Fprintf(tw, "%s:%d\t%s\t%d\n", hit.File, hit.Line, hit.Func, hit.Count)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the hits to w as a JSON array.
+func (h *HitCounter) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h.Hits())
+}
+
+// Report writes the hits to ReportTo, as JSON if JSON is set. Defer it in main to get a report when the program ends.
+// ErrOut calls it before exiting.
+func (h *HitCounter) Report() error {
+	w := h.ReportTo
+	if w == nil {
+		w = os.Stderr
+	}
+	if h.JSON {
+		return h.WriteJSON(w)
+	}
+	return h.WriteReport(w)
+}
@@ -0,0 +1,55 @@
+package verbose
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHitCounter(t *testing.T) {
+	needsVerbose(t)
+	v := New(io.Discard)
+	v.Hits = NewHitCounter()
+	for i := 0; i < 3; i++ {
+		v.Println("loop", i)
+	}
+	v.V = true
+	v.Printf("once\n")
+	v.Err(nil, "no error")
+	v.Printj(1)
+
+	hits := v.Hits.Hits()
+	if len(hits) != 4 {
+		t.Fatalf("got %d call sites, want 4: %+v", len(hits), hits)
+	}
+	for i, want := range []int64{3, 1, 1, 1} {
+		if hits[i].Count != want || filepath.Base(hits[i].File) != "hits_test.go" ||
+			!strings.HasSuffix(hits[i].Func, "TestHitCounter") {
+			t.Errorf("hit %d = %+v, want count %d in TestHitCounter", i, hits[i], want)
+		}
+	}
+	if hits[0].Line >= hits[1].Line {
+		t.Errorf("hits not ordered by line: %+v", hits)
+	}
+
+	var buf bytes.Buffer
+	v.Hits.ReportTo = &buf
+	v.Hits.JSON = true
+	if err := v.Hits.Report(); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Hit
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 4 {
+		t.Errorf("JSON report %q: %v", buf.String(), err)
+	}
+
+	v.Hits.Reset()
+	v.ErrOut(errors.New("boom"), "counted once")
+	if hits := v.Hits.Hits(); len(hits) != 1 || hits[0].Count != 1 {
+		t.Errorf("after ErrOut hits = %+v", hits)
+	}
+}
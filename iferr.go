@@ -1,30 +1,15 @@
 package verbose
 
 import (
+	"fmt"
 	"os"
 	"runtime"
 )
 
 // err out will always print if an error is present. to only print when verb is true use Err. Prints to whatever verbose.Out is set to.
 func (v *Verb) ErrOut(err error, str string, e ...bool) bool {
-	var exit bool
-	// We're going to use verbose.Printf
-	vOrigState := v.V
-	if len(e) > 0 {
-		exit = e[0]
-	}
-	if err != nil {
-		_, file, line, _ := runtime.Caller(1)
-		_, cfile, cline, _ := runtime.Caller(2)
-		v.V = true
-		v.Printf("error: %v -- %v\n\tfile: %v line: %v\n\tfile: %v line: %v\n", str, err, file, line, cfile, cline)
-		if exit {
-			os.Exit(1)
-		}
-		v.V = vOrigState
-		return true
-	}
-	return false
+	v.hit()
+	return v.errOut(err, str, len(e) > 0 && e[0])
 }
 
 // Only prints an error when it's verb.V is set to true
@@ -34,16 +19,36 @@ func (v *Verb) ErrOut(err error, str string, e ...bool) bool {
 // }
 
 func (v *Verb) Err(err error, str string, e ...bool) bool {
-	var exit bool
-	// We're going to use verbose.Printf
-	if len(e) > 0 {
-		exit = e[0]
+	if !verboseBuild {
+		return err != nil
 	}
-	if v.V {
-		return v.ErrOut(err, str, exit)
+	v.hit()
+	if v.Enabled() {
+		return v.errOut(err, str, len(e) > 0 && e[0])
 	}
 	if err != nil {
 		return true
 	}
 	return false
 }
+
+// errOut prints err with the file and line of the ErrOut or Err call and of the function that called it, and exits
+// if exit is set. It must be called directly from ErrOut or Err.
+func (v *Verb) errOut(err error, str string, exit bool) bool {
+	if err != nil {
+		_, file, line, _ := runtime.Caller(2)
+		_, cfile, cline, _ := runtime.Caller(3)
+		mu.Lock()
+		v.output(v.Out, 2, fmt.Appendf(nil, "error: %v -- %v\n\tfile: %v line: %v\n\tfile: %v line: %v\n", str, err, file, line, cfile, cline))
+		mu.Unlock()
+		if exit {
+			v.Close()
+			if v.Hits != nil {
+				v.Hits.Report()
+			}
+			os.Exit(1)
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package verbose
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Lazy is an argument that is only computed when the message is printed. Arguments to Println are evaluated even
+// when verbose mode is off, wrap the expensive ones:
+//
+//	verb.Println("state:", verbose.Lazy(func() any { return expensiveDump() }))
+//
+// The function runs each time the value is formatted, and not at all when V is false.
+type Lazy func() any
+
+// Format formats the value returned by l with the verb and flags it was printed with.
+func (l Lazy) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, fmt.FormatString(f, verb), l())
+}
+
+// MarshalJSON marshals the value returned by l, so a Lazy can be passed to Printj.
+func (l Lazy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l())
+}
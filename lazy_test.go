@@ -0,0 +1,56 @@
+package verbose
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLazy(t *testing.T) {
+	var buf bytes.Buffer
+	v := New(&buf)
+	calls := 0
+	dump := func() any { calls++; return 42 }
+	args := func() []any { calls++; return []any{"state:", 42} }
+
+	v.Println("state:", Lazy(dump))
+	v.Printf("state: %05d\n", Lazy(dump))
+	v.PrintlnFunc(args)
+	v.PrintfFunc("%s %d\n", args)
+	v.Printj(Lazy(dump))
+	if calls != 0 || buf.Len() != 0 {
+		t.Fatalf("verbose off: %d calls, output %q", calls, buf.String())
+	}
+
+	needsVerbose(t)
+	v.V = true
+	v.Println("state:", Lazy(dump))
+	v.Printf("state: %05d\n", Lazy(dump))
+	v.PrintlnFunc(args)
+	v.PrintfFunc("%s %d\n", args)
+	v.Printj(Lazy(func() any { return map[string]Secret{"token": "abc"} }))
+	want := "state: 42\nstate: 00042\nstate: 42\nstate: 42\n{\n  \"token\": \"****\"\n}\n"
+	if calls != 4 || buf.String() != want {
+		t.Errorf("verbose on: %d calls, got %q, want %q", calls, buf.String(), want)
+	}
+}
+
+func TestLazy_PrintjPrints(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.V = true
+	done := make(chan struct{})
+	go func() {
+		v.Printj(Lazy(func() any { v.Println("computing"); return 1 }))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Printj of a Lazy that prints deadlocked")
+	}
+	if want := "computing\n1\n"; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
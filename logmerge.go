@@ -0,0 +1,92 @@
+package verbose
+
+import (
+	"container/heap"
+	"io"
+	"time"
+)
+
+// LogMerger interleaves the Records of several logs in time order, for following a pipeline of programs that each
+// write their own verbose log. Set Source on each LogReader to tell the records apart. Each reader can have its own
+// Layout. Records without a date stay right after the record before them in their own log.
+//
+//	a := verbose.NewLogReader(fa, "2006-01-02 15:04:05")
+//	a.Source = "extract"
+//	b := verbose.NewLogReader(fb, time.RFC3339)
+//	b.Source = "load"
+//	m := verbose.NewLogMerger(a, b)
+//	for {
+//		rec, err := m.Next()
+//		...
+//	}
+type LogMerger struct {
+	readers []*LogReader
+	heads   mergeHeap
+	started bool
+	err     error
+}
+
+// NewLogMerger returns a LogMerger reading from readers.
+func NewLogMerger(readers ...*LogReader) *LogMerger {
+	return &LogMerger{readers: readers}
+}
+
+type mergeHead struct {
+	rec Record
+	// at is the time used for ordering: the record's own, or the last one seen in its log.
+	at  time.Time
+	src int
+}
+
+type mergeHeap []mergeHead
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].src < h[j].src
+	}
+	return h[i].at.Before(h[j].at)
+}
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x any)   { *h = append(*h, x.(mergeHead)) }
+func (h *mergeHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// advance reads the next record of log src onto the heap. last is the ordering time of its previous record.
+func (m *LogMerger) advance(src int, last time.Time) {
+	rec, err := m.readers[src].Next()
+	if err != nil {
+		if err != io.EOF && m.err == nil {
+			m.err = err
+		}
+		return
+	}
+	at := rec.Time
+	if at.IsZero() {
+		at = last
+	}
+	heap.Push(&m.heads, mergeHead{rec: rec, at: at, src: src})
+}
+
+// Next returns the earliest Record left in any of the logs, or io.EOF when they are all done.
+func (m *LogMerger) Next() (Record, error) {
+	if !m.started {
+		m.started = true
+		for i := range m.readers {
+			m.advance(i, time.Time{})
+		}
+	}
+	if m.err != nil {
+		return Record{}, m.err
+	}
+	if len(m.heads) == 0 {
+		return Record{}, io.EOF
+	}
+	h := heap.Pop(&m.heads).(mergeHead)
+	m.advance(h.src, h.at)
+	return h.rec, nil
+}
@@ -0,0 +1,51 @@
+package verbose
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogMerger(t *testing.T) {
+	a := NewLogReader(strings.NewReader(
+		"2024-05-01 10:00:00 a.go:1 extract start\n"+
+			"2024-05-01 10:00:02 a.go:2 rows:\n"+
+			"{\n  \"n\": 3\n}\n"+
+			"2024-05-01 10:00:05 a.go:3 extract done\n"), "2006-01-02 15:04:05")
+	a.Source = "extract"
+	b := NewLogReader(strings.NewReader(
+		"Wednesday May 01 2024, 10:00:01 load start\n"+
+			"Wednesday May 01 2024, 10:00:02 load rows\n"+
+			"Wednesday May 01 2024, 10:00:09 load done\n"), TimeFormatStr("%A %B %d %Y, %H:%M:%S"))
+	b.Source = "load"
+
+	m := NewLogMerger(a, b)
+	var got []string
+	var last time.Time
+	for {
+		rec, err := m.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Time.Before(last) {
+			t.Errorf("%s out of order", rec.Raw)
+		}
+		last = rec.Time
+		got = append(got, rec.Source+": "+strings.SplitN(rec.Msg, "\n", 2)[0])
+	}
+	want := []string{
+		"extract: extract start",
+		"load: load start",
+		"extract: rows:",
+		"load: load rows",
+		"extract: extract done",
+		"load: load done",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("merged:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,197 @@
+package verbose
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Record is one message read back from verbose output.
+type Record struct {
+	// Time is zero if the message had no date.
+	Time time.Time
+	// File and Line are the call site if the message had one.
+	File string
+	Line int
+	// Msg is the message without the date and line prefix. Messages that span several lines, like Printj output,
+	// keep their newlines.
+	Msg string
+	// Raw is the text as it was read.
+	Raw string
+	// Source names where the record came from, see MergeLogs.
+	Source string
+	// LineNo is the line of the log the record starts on, counting from 1.
+	LineNo int
+}
+
+var callSiteRe = regexp.MustCompile(`^([\w.+-]+\.go|\?\?\?):(\d+)$`)
+
+// LogReader reads Records from output written by a Verb. Lines that don't start a new message, such as the
+// indented lines of Printj, are added to the message before them.
+type LogReader struct {
+	// Layout is the Go layout of the dates, usually the Verb's Dformat. Leave it empty if the log has no dates.
+	Layout string
+	// Strftime is the date(1) format of the dates, the Verb's Strftime. When set it is used instead of Layout.
+	Strftime string
+	// Delimeter is the Verb's Delimeter. Defaults to a space.
+	Delimeter string
+	// Location is the time zone of dates without one. Defaults to time.Local.
+	Location *time.Location
+	// Source is copied to every Record.
+	Source string
+
+	sc      *bufio.Scanner
+	tl      *TimeLayout
+	lineNo  int
+	pending *Record
+	err     error
+}
+
+// NewLogReader returns a LogReader reading r. layout is the Go layout of the dates, or "" if there are none.
+func NewLogReader(r io.Reader, layout string) *LogReader {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &LogReader{Layout: layout, Delimeter: " ", sc: sc}
+}
+
+// Next returns the next Record, or io.EOF when there are no more.
+func (lr *LogReader) Next() (Record, error) {
+	for lr.err == nil {
+		if !lr.sc.Scan() {
+			lr.err = lr.sc.Err()
+			if lr.err == nil {
+				lr.err = io.EOF
+			}
+			break
+		}
+		lr.lineNo++
+		line := strings.TrimSuffix(lr.sc.Text(), "\r")
+		rec, ok := lr.ParseLine(line)
+		if !ok && lr.pending != nil {
+			lr.pending.Msg += "\n" + line
+			lr.pending.Raw += "\n" + line
+			continue
+		}
+		if !ok {
+			// Nothing to add it to, at the start of the log or after Flush, so it is a message of its own.
+			rec.Msg = line
+		}
+		rec.LineNo = lr.lineNo
+		prev := lr.pending
+		lr.pending = &rec
+		if prev != nil {
+			return *prev, nil
+		}
+	}
+	if lr.pending != nil {
+		rec := *lr.pending
+		lr.pending = nil
+		return rec, nil
+	}
+	return Record{}, lr.err
+}
+
+// Flush returns the last message read, which Next holds back until the next one starts in case more of its lines
+// follow, and forgets it. ok is false if there is none. Call it when the input has gone quiet, as verbview --follow
+// does, so the newest message shows before another is written. It may be called from the reader Next is reading.
+func (lr *LogReader) Flush() (rec Record, ok bool) {
+	if lr.pending == nil {
+		return Record{}, false
+	}
+	rec = *lr.pending
+	lr.pending = nil
+	return rec, true
+}
+
+// ParseLine splits a single line into a Record. ok is false if the line doesn't start a new message: with a Layout
+// that is a line without a date, without one it is an indented line or the closing brace of Printj output.
+func (lr *LogReader) ParseLine(line string) (rec Record, ok bool) {
+	rec = Record{Raw: line, Source: lr.Source}
+	delim := lr.Delimeter
+	if delim == "" {
+		delim = " "
+	}
+	rest := line
+	if lr.Strftime != "" {
+		t, after, found := lr.parseStrftime(line, delim)
+		if !found {
+			return rec, false
+		}
+		rec.Time = t
+		rest = after
+	} else if layout := strings.TrimSpace(lr.Layout); layout != "" {
+		t, after, found := lr.parseDate(line, layout, delim)
+		if !found {
+			return rec, false
+		}
+		rec.Time = t
+		rest = after
+	} else if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '}' || line[0] == ']' {
+		return rec, false
+	}
+
+	site, after, found := strings.Cut(rest, delim)
+	if !found {
+		site = rest
+		after = ""
+	}
+	if m := callSiteRe.FindStringSubmatch(strings.TrimSpace(site)); m != nil {
+		rec.File = m[1]
+		rec.Line, _ = strconv.Atoi(m[2])
+		rest = after
+	}
+	rec.Msg = rest
+	return rec, true
+}
+
+// parseDate looks for a date in layout at the start of line, ending where a delimiter or the line ends.
+func (lr *LogReader) parseDate(line, layout, delim string) (time.Time, string, bool) {
+	loc := lr.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	for i := 0; i <= len(line); i++ {
+		end := i == len(line)
+		if !end && !strings.HasPrefix(line[i:], delim) {
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(line[:i]), loc); err == nil {
+			if end {
+				return t, "", true
+			}
+			return t, strings.TrimLeft(line[i+len(delim):], " "), true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// parseStrftime reads a date in the Strftime format at the start of line, followed by a delimiter or the line's end.
+func (lr *LogReader) parseStrftime(line, delim string) (time.Time, string, bool) {
+	format := strings.TrimSpace(lr.Strftime)
+	if lr.tl == nil || lr.tl.String() != format {
+		tl, err := CompileTime(format)
+		if err != nil {
+			return time.Time{}, "", false
+		}
+		lr.tl = tl
+	}
+	loc := lr.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t, n, err := lr.tl.parsePrefix(line, loc)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	rest := line[n:]
+	if rest == "" {
+		return t, "", true
+	}
+	if !strings.HasPrefix(rest, delim) {
+		return time.Time{}, "", false
+	}
+	return t, strings.TrimLeft(rest[len(delim):], " "), true
+}
@@ -0,0 +1,176 @@
+package verbose
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogReader(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	dformat := "%A %B %d %Y, %I:%M:%S %P"
+	v := New(&buf, dformat)
+	v.V = true
+	v.PrintLine = true
+	v.Delimeter = "|"
+	start := time.Now().Truncate(time.Second)
+	v.Println("first message")
+	v.PrintDate = false
+	v.PrintLine = false
+	v.Printj(struct{ Name string }{"John"})
+	v.PrintDate = true
+	v.Printf("last %s", "message")
+
+	lr := NewLogReader(&buf, v.Dformat)
+	lr.Delimeter = v.Delimeter
+	var recs []Record
+	for {
+		rec, err := lr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, rec)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("got %d records, want 2: %+v", len(recs), recs)
+	}
+	first := recs[0]
+	if first.Time.Before(start) || first.Time.After(time.Now()) {
+		t.Errorf("time %v, want around %v", first.Time, start)
+	}
+	if first.File != "logread_test.go" || first.Line == 0 || first.LineNo != 1 {
+		t.Errorf("call site %s:%d line %d", first.File, first.Line, first.LineNo)
+	}
+	if want := "first message\n{\n  \"Name\": \"John\"\n}"; first.Msg != want {
+		t.Errorf("Msg = %q, want %q", first.Msg, want)
+	}
+	if last := recs[1]; last.Msg != "last message" || last.File != "" || last.LineNo != 5 {
+		t.Errorf("last record = %+v", last)
+	}
+}
+
+func TestLogReader_NoDate(t *testing.T) {
+	in := "main.go:12 starting\n{\n  \"a\": 1\n}\nplain line\n"
+	lr := NewLogReader(strings.NewReader(in), "")
+	var got []string
+	for {
+		rec, err := lr.Next()
+		if err != nil {
+			break
+		}
+		got = append(got, rec.File+"|"+rec.Msg)
+	}
+	want := []string{"main.go|starting", "|{\n  \"a\": 1\n}", "|plain line"}
+	if strings.Join(got, "\n--\n") != strings.Join(want, "\n--\n") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLogReader_Strftime(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	dformat := "%e %b %-l:%M:%S.%3N%P"
+	v := New(&buf, dformat)
+	v.V = true
+	v.Clock = NewStepClock(time.Date(2024, 3, 5, 14, 7, 9, 250e6, time.Local), time.Second)
+	v.Println("first")
+	v.Println("second")
+	if v.Strftime == "" {
+		t.Fatal("New didn't set Strftime")
+	}
+
+	lr := NewLogReader(&buf, v.Dformat)
+	lr.Strftime = v.Strftime
+	for i, want := range []string{"first", "second"} {
+		rec, err := lr.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantTime := time.Date(0, 3, 5, 14, 7, 9+i, 250e6, time.Local)
+		if rec.Msg != want || !rec.Time.Equal(wantTime) {
+			t.Errorf("got %q at %v, want %q at %v", rec.Msg, rec.Time, want, wantTime)
+		}
+	}
+}
+
+func TestLogReader_Fraction(t *testing.T) {
+	needsVerbose(t)
+	when := time.Date(2024, 3, 5, 14, 7, 9, 123456789, time.Local)
+	for format, nsec := range map[string]int{
+		"%T%N":          123000000,
+		"%F %T%N":       123000000,
+		"%T%N %k":       123000000,
+		"%F %T.%3N":     123000000,
+		"%F %T.%N":      123456789,
+		"%I:%M:%S%N %P": 123000000,
+	} {
+		var buf bytes.Buffer
+		v := New(&buf, format)
+		v.V = true
+		v.Clock = NewStepClock(when, time.Second)
+		v.Println("first")
+		v.Println("second")
+
+		lr := NewLogReader(&buf, v.Dformat)
+		lr.Strftime = v.Strftime
+		for i, want := range []string{"first", "second"} {
+			rec, err := lr.Next()
+			if err != nil {
+				t.Fatalf("%q: %v", format, err)
+			}
+			h, m, s := rec.Time.Clock()
+			if rec.Msg != want || h != 14 || m != 7 || s != 9+i || rec.Time.Nanosecond() != nsec {
+				t.Errorf("%q: got %q at %v, want %q at 14:07:%02d.%09d", format, rec.Msg, rec.Time, want, 9+i, nsec)
+			}
+		}
+	}
+}
+
+// idleReader returns its chunks one per Read and calls idle between them, like a followed file that stops growing.
+type idleReader struct {
+	chunks []string
+	idle   func()
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	if r.idle != nil {
+		r.idle()
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+func TestLogReader_Flush(t *testing.T) {
+	var got []string
+	r := &idleReader{chunks: []string{"a.go:1 first\n  more\n", "a.go:2 second\n", "  late\n"}}
+	lr := NewLogReader(r, "")
+	r.idle = func() {
+		if rec, ok := lr.Flush(); ok {
+			got = append(got, "flushed "+rec.Msg)
+		}
+	}
+	for {
+		rec, err := lr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, "next "+rec.Msg)
+	}
+	want := []string{"flushed first\n  more", "flushed second", "next   late"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
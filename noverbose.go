@@ -0,0 +1,39 @@
+//go:build noverbose
+
+package verbose
+
+import "io"
+
+// Built with -tags noverbose every Print method is an empty function the compiler inlines away, so verbose calls
+// cost nothing in production builds while the source stays the same. V has no effect, Printj never marshals and
+// Err only reports whether err is set. ErrOut still prints errors, since they are not verbose output.
+
+// verboseBuild is false when built with the noverbose tag.
+const verboseBuild = false
+
+// Print does nothing in a noverbose build.
+func (v *Verb) Print(a ...any) {}
+
+// Println does nothing in a noverbose build.
+func (v *Verb) Println(a ...any) {}
+
+// Printf does nothing in a noverbose build.
+func (v *Verb) Printf(format string, a ...any) {}
+
+// PrintlnFunc does nothing in a noverbose build, f is never called.
+func (v *Verb) PrintlnFunc(f func() []any) {}
+
+// PrintfFunc does nothing in a noverbose build, f is never called.
+func (v *Verb) PrintfFunc(format string, f func() []any) {}
+
+// Printj does nothing in a noverbose build.
+func (v *Verb) Printj(data interface{}) {}
+
+// Fprint does nothing in a noverbose build.
+func (v *Verb) Fprint(w io.Writer, a ...any) {}
+
+// Fprintln does nothing in a noverbose build.
+func (v *Verb) Fprintln(w io.Writer, a ...any) {}
+
+// Fprintf does nothing in a noverbose build.
+func (v *Verb) Fprintf(w io.Writer, format string, a ...any) {}
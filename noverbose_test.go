@@ -0,0 +1,46 @@
+package verbose
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// needsVerbose skips tests that check printed output when built with -tags noverbose.
+func needsVerbose(t testing.TB) {
+	t.Helper()
+	if !verboseBuild {
+		t.Skip("verbose output is compiled out by the noverbose tag")
+	}
+}
+
+func TestNoverbose(t *testing.T) {
+	if verboseBuild {
+		t.Skip("run with -tags noverbose")
+	}
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.V = true
+	v.PrintDate = true
+	v.PrintLine = true
+
+	v.Print("print")
+	v.Println("println")
+	v.Printf("printf %d\n", 1)
+	v.Printj(make(chan int)) // would report a marshal error if it were marshaled
+	v.Fprintln(&buf, "fprintln")
+	if v.Err(nil, "no error") {
+		t.Error("Err(nil) = true")
+	}
+	if !v.Err(errors.New("boom"), "failed") {
+		t.Error("Err(err) = false")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("noverbose build printed %q", buf.String())
+	}
+
+	n := 42
+	if allocs := testing.AllocsPerRun(100, func() { v.Printf("n=%d\n", n) }); allocs != 0 {
+		t.Errorf("Printf allocated %v times, want 0", allocs)
+	}
+}
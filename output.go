@@ -0,0 +1,142 @@
+package verbose
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// mu serializes building output and guards the settings changed at run time, like Out and Dformat. It is released
+// for the write itself, which the Verb's own write lock serializes. Verbose mode is not under it: Enabled and SetV
+// keep it in the Verb's atomic on field. mu also guards lineBuf and the frame cache below.
+var mu sync.Mutex
+
+// lineBuf is where output builds each line, reused so printing doesn't allocate. mu must be held.
+var lineBuf []byte
+
+// maxBuf is the largest buffer kept for reuse, so one huge message doesn't pin its memory.
+const maxBuf = 64 << 10
+
+// bufPool holds the buffers the Print methods format messages into. Formatting happens before mu is taken, so a
+// String method or Lazy value may print too.
+var bufPool = sync.Pool{New: func() any { return new([]byte) }}
+
+func getBuf() *[]byte {
+	return bufPool.Get().(*[]byte)
+}
+
+func putBuf(b *[]byte) {
+	if cap(*b) > maxBuf {
+		return
+	}
+	*b = (*b)[:0]
+	bufPool.Put(b)
+}
+
+// print writes a message formatted by one of the exported Print methods to w, or to Out when w is nil.
+func (v *Verb) print(w io.Writer, msg []byte) {
+	mu.Lock()
+	defer mu.Unlock()
+	if w == nil {
+		if v.Out == nil {
+			v.Out = os.Stdout
+		}
+		w = v.Out
+	}
+	v.output(w, 2, msg)
+}
+
+// frame is the base file name and line of a call site.
+type frame struct {
+	file string
+	line int
+}
+
+// frames caches call sites by program counter. Resolving a PC to a file and line is the slowest part of PrintLine and
+// a program only has so many verbose statements. mu must be held.
+var frames = map[uintptr]frame{}
+
+// pcBuf receives the PC looked up by caller, a package variable since a local one escapes to the heap. mu must be held.
+var pcBuf [1]uintptr
+
+// caller returns the call site skip frames above the function calling caller, with 0 being that function's caller.
+// mu must be held.
+func caller(skip int) frame {
+	if runtime.Callers(skip+3, pcBuf[:]) == 0 {
+		return frame{"???", 0}
+	}
+	pc := pcBuf[0]
+	f, ok := frames[pc]
+	if !ok {
+		fr, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		f = frame{filepath.Base(fr.File), fr.Line}
+		if fr.File == "" {
+			f.file = "???"
+		}
+		frames[pc] = f
+	}
+	return f
+}
+
+// output writes msg to w, preceded by the date and the line number of the caller
+// when PrintDate and PrintLine are set, and passes it on to subscribers. skip is the
+// number of frames between output and the user's call, 1 when called from the exported
+// Print method. mu must be held.
+func (v *Verb) output(w io.Writer, skip int, msg []byte) {
+	if w == nil {
+		w = os.Stdout
+	}
+	if v.Delimeter == "" {
+		v.Delimeter = " "
+	}
+	if v.Redactor != nil || v.Anonymizer != nil {
+		msg = []byte(v.filter(string(msg)))
+	}
+	now := v.now()
+	var f frame
+	if v.PrintLine || v.hub != nil {
+		f = caller(skip)
+	}
+	b := lineBuf[:0]
+	if v.PrintDate == true {
+		b = v.appendDate(b, now)
+		b = append(b, v.Delimeter...)
+	}
+	if v.PrintLine {
+		b = append(b, f.file...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(f.line), 10)
+		b = append(b, v.Delimeter...)
+	}
+	b = append(b, msg...)
+	if v.hub != nil {
+		v.publish(Entry{Time: now, File: f.file, Line: f.line, Msg: string(msg), Text: string(b)})
+	}
+	v.write(w, b)
+	if cap(b) <= maxBuf {
+		lineBuf = b
+	}
+}
+
+// appendDate appends t formatted with Strftime, or with Dformat when that is not set.
+func (v *Verb) appendDate(b []byte, t time.Time) []byte {
+	if v.Strftime != "" {
+		return AppendStrftime(b, v.Strftime, t)
+	}
+	return t.AppendFormat(b, v.Dformat)
+}
+
+// filter applies the redaction and anonymisation rules to a message before it is written.
+func (v *Verb) filter(msg string) string {
+	if v.Redactor != nil {
+		msg = v.Redactor.Redact(msg)
+	}
+	if v.Anonymizer != nil {
+		msg = v.Anonymizer.Anonymize(msg)
+	}
+	return msg
+}
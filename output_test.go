@@ -0,0 +1,82 @@
+package verbose
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerb_SetV(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.V = true
+	v.Print("v ")
+	v.SetV(false)
+	v.Print("off ")
+	v.V = true // SetV wins from now on
+	v.Print("ignored ")
+	v.SetV(true)
+	v.Print("on")
+	if got := buf.String(); got != "v on" {
+		t.Errorf("got %q", got)
+	}
+
+	// Checking V doesn't take the output lock, so a slow writer elsewhere doesn't hold up disabled calls.
+	v.SetV(false)
+	mu.Lock()
+	done := make(chan bool)
+	go func() {
+		v.Println("disabled")
+		done <- v.Enabled()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("a disabled Println waited for the output lock")
+	}
+	mu.Unlock()
+}
+
+func TestOutputCaller(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.V = true
+	v.PrintLine = true
+	v.Delimeter = "|"
+	var want []string
+	for i := 0; i < 2; i++ {
+		_, _, line, _ := runtime.Caller(0)
+		v.Println("first", i) // the frame cache must give the same line again
+		want = append(want, fmt.Sprintf("output_test.go:%d|first %d", line+1, i))
+		_, _, line, _ = runtime.Caller(0)
+		v.Fprintf(&buf, "second %d\n", i)
+		want = append(want, fmt.Sprintf("output_test.go:%d|second %d", line+1, i))
+	}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// raceEnabled is set by race_test.go. The race detector makes sync.Pool drop buffers, so allocations aren't checked.
+var raceEnabled bool
+
+func TestEnabledAllocs(t *testing.T) {
+	needsVerbose(t)
+	if raceEnabled {
+		t.Skip("allocations are not stable under the race detector")
+	}
+	dformat := "%F %T"
+	v := New(io.Discard, dformat)
+	v.V = true
+	v.PrintLine = true
+	n := 42
+	if allocs := testing.AllocsPerRun(100, func() { v.Printf("state: %d\n", n) }); allocs != 0 {
+		t.Errorf("enabled Printf allocated %v times, want 0", allocs)
+	}
+}
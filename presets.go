@@ -0,0 +1,39 @@
+package verbose
+
+import (
+	"strings"
+	"time"
+)
+
+// preset is a named date format: a Go layout, or a date(1) format when there is no layout for it.
+type preset struct {
+	layout   string
+	strftime string
+}
+
+var presets = map[string]preset{
+	"rfc3339":     {layout: time.RFC3339},
+	"rfc3339nano": {layout: time.RFC3339Nano},
+	"iso8601":     {layout: "2006-01-02T15:04:05-07:00"},
+	"syslog":      {layout: time.Stamp},
+	"apache":      {layout: "02/Jan/2006:15:04:05 -0700"},
+	"kitchen":     {layout: time.Kitchen},
+	"epoch":       {strftime: "%s"},
+	"epochms":     {strftime: "%s%3N"},
+}
+
+// PresetFormat returns the date format of a preset name New accepts, as a Go layout or, for epoch and epochms, a
+// date(1) format. ok is false if name isn't a preset. The names are:
+//
+//	rfc3339       2006-01-02T15:04:05Z07:00
+//	rfc3339nano   2006-01-02T15:04:05.999999999Z07:00
+//	iso8601       2006-01-02T15:04:05-07:00, like date --iso-8601=seconds
+//	syslog        Jan _2 15:04:05
+//	apache        02/Jan/2006:15:04:05 -0700, the common log format
+//	kitchen       3:04PM
+//	epoch         seconds since 1970, %s
+//	epochms       milliseconds since 1970, %s%3N
+func PresetFormat(name string) (layout, strftime string, ok bool) {
+	p, ok := presets[strings.ToLower(strings.TrimSpace(name))]
+	return p.layout, p.strftime, ok
+}
@@ -0,0 +1,62 @@
+package verbose
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestNew_Preset(t *testing.T) {
+	needsVerbose(t)
+	nst := time.FixedZone("NST", -(3*3600 + 30*60))
+	when := time.Date(2021, 1, 3, 9, 5, 7, 123456789, nst)
+	tests := []struct {
+		preset string
+		want   string
+	}{
+		{"rfc3339", "2021-01-03T09:05:07-03:30"},
+		{"RFC3339Nano", "2021-01-03T09:05:07.123456789-03:30"},
+		{"iso8601", "2021-01-03T09:05:07-03:30"},
+		{"syslog", "Jan  3 09:05:07"},
+		{"apache", "03/Jan/2021:09:05:07 -0330"},
+		{"kitchen", "9:05AM"},
+		{"epoch", "1609677307"},
+		{"epochms", "1609677307123"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		v := New(&buf, tt.preset)
+		v.V = true
+		v.Delimeter = "|"
+		v.Clock = NewStepClock(when, 0)
+		v.Println("x")
+		if got := buf.String(); got != tt.want+"|x\n" {
+			t.Errorf("New(%q) printed %q, want %q", tt.preset, got, tt.want+"|x\n")
+		}
+	}
+	if _, _, ok := PresetFormat("%F"); ok {
+		t.Errorf("PresetFormat(%q) found a preset", "%F")
+	}
+}
+
+func TestVerb_Location(t *testing.T) {
+	needsVerbose(t)
+	when := time.Date(2021, 1, 3, 9, 5, 7, 0, time.FixedZone("NST", -(3*3600+30*60)))
+	var buf bytes.Buffer
+	v := New(&buf, "rfc3339", time.UTC)
+	v.V = true
+	v.Clock = NewStepClock(when, 0)
+	v.Println("utc")
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	v.Location = tokyo
+	v.Println("tokyo")
+	v.Location = nil
+	v.Println("clock")
+	want := "2021-01-03T12:35:07Z utc\n2021-01-03T21:35:07+09:00 tokyo\n2021-01-03T09:05:07-03:30 clock\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
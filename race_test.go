@@ -0,0 +1,7 @@
+//go:build race
+
+package verbose
+
+func init() {
+	raceEnabled = true
+}
@@ -0,0 +1,283 @@
+package verbose
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultMask is what a redacted value is replaced with.
+const DefaultMask = "****"
+
+// DefaultRedactFields are the field names masked by NewRedactor when none are given.
+var DefaultRedactFields = []string{"password", "passwd", "secret", "token", "Authorization"}
+
+// Secret holds a value that should never show up in verbose output. It prints as DefaultMask no matter which verb is
+// used, and marshals to DefaultMask in Printj. Convert it back with string(s) where the real value is needed.
+//
+//	verb.Println("Connecting as", user, verbose.Secret(password))
+//
+// fmt can't call the methods of an unexported struct field, so a Secret in one prints as the plain string:
+// struct{ pw Secret } prints {hunter2}. Keep secrets in exported fields, or tag the field `verbose:"redact"` and use
+// Printj.
+type Secret string
+
+// String returns the mask, never the secret.
+func (s Secret) String() string {
+	return DefaultMask
+}
+
+// GoString keeps %#v from printing the secret.
+func (s Secret) GoString() string {
+	return strconv.Quote(DefaultMask)
+}
+
+// Format implements fmt.Formatter so every verb, including %x and %q, prints the mask.
+func (s Secret) Format(f fmt.State, c rune) {
+	if c == 'q' {
+		io.WriteString(f, strconv.Quote(DefaultMask))
+		return
+	}
+	io.WriteString(f, DefaultMask)
+}
+
+// MarshalJSON marshals the mask instead of the secret.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(DefaultMask)), nil
+}
+
+// Redactor masks secrets in verbose output. Field rules mask the value following a field name, as in
+// password=hunter2, "token": "abc" or Authorization: Bearer abc. Pattern rules mask whatever a regular expression
+// matches, or only its capture groups if it has any.
+//
+//	verb.Redactor = verbose.NewRedactor()
+//	verb.Redactor.AddPattern(`\b\d{4}-\d{4}-\d{4}-\d{4}\b`)
+//	verb.Println("Query:", query)
+type Redactor struct {
+	// Mask replaces redacted values. Defaults to DefaultMask.
+	Mask string
+
+	mu       sync.RWMutex
+	fields   []*regexp.Regexp
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor returns a Redactor masking the given field names, or DefaultRedactFields if none are given.
+func NewRedactor(fields ...string) *Redactor {
+	if len(fields) == 0 {
+		fields = DefaultRedactFields
+	}
+	r := &Redactor{Mask: DefaultMask}
+	for _, f := range fields {
+		r.AddField(f)
+	}
+	return r
+}
+
+// AddField masks the value that follows name and a ':' or '='. Matching is case insensitive and also catches
+// names that contain it, so "token" covers access_token and X-Auth-Token.
+func (r *Redactor) AddField(name string) {
+	re := regexp.MustCompile(`(?i)[\w.-]*` + regexp.QuoteMeta(name) + `[\w-]*["']?\s*[:=]\s*(?:(?:bearer|basic|token)\s+)?("[^"]*"|'[^']*'|[^\s,;&"'}\]]+)`)
+	r.mu.Lock()
+	r.fields = append(r.fields, re)
+	r.mu.Unlock()
+}
+
+// AddPattern masks every match of the regular expression expr. If expr has capture groups only the groups are masked.
+func (r *Redactor) AddPattern(expr string) error {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.patterns = append(r.patterns, re)
+	r.mu.Unlock()
+	return nil
+}
+
+// Redact returns s with every field and pattern match masked.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+	mask := r.Mask
+	if mask == "" {
+		mask = DefaultMask
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, re := range r.fields {
+		s = maskMatches(re, s, mask, true)
+	}
+	for _, re := range r.patterns {
+		s = maskMatches(re, s, mask, false)
+	}
+	return s
+}
+
+// maskMatches replaces the capture groups of each match of re with mask, or the whole match when re has no groups.
+// With keepQuotes a quoted group keeps its quotes so JSON output stays valid.
+func maskMatches(re *regexp.Regexp, s, mask string, keepQuotes bool) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		groups := m[2:]
+		if len(groups) == 0 {
+			groups = m[:2]
+		}
+		for i := 0; i < len(groups); i += 2 {
+			start, end := groups[i], groups[i+1]
+			if start < 0 || start < last {
+				continue
+			}
+			b.WriteString(s[last:start])
+			val := s[start:end]
+			if keepQuotes && len(val) >= 2 && (val[0] == '"' || val[0] == '\'') {
+				b.WriteByte(val[0])
+				b.WriteString(mask)
+				b.WriteByte(val[0])
+			} else {
+				b.WriteString(mask)
+			}
+			last = end
+		}
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// mask returns the mask configured on the Verb's Redactor.
+func (v *Verb) mask() string {
+	if v.Redactor != nil && v.Redactor.Mask != "" {
+		return v.Redactor.Mask
+	}
+	return DefaultMask
+}
+
+// redactTagged returns a copy of data with every struct field tagged `verbose:"redact"` masked. String fields are
+// set to mask, fields of other kinds are cleared to their zero value. data itself is never modified.
+func redactTagged(data any, mask string) any {
+	if data == nil {
+		return nil
+	}
+	v := reflect.ValueOf(data)
+	if !hasRedactTag(v.Type()) {
+		return data
+	}
+	return redactValue(v, mask, 0).Interface()
+}
+
+// maxRedactDepth stops redactValue on cyclic data. encoding/json reports the cycle itself.
+const maxRedactDepth = 64
+
+func redactValue(v reflect.Value, mask string, depth int) reflect.Value {
+	if depth > maxRedactDepth || !hasRedactTag(v.Type()) {
+		return v
+	}
+	depth++
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(redactValue(v.Elem(), mask, depth))
+		return p
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(redactValue(v.Elem(), mask, depth))
+		return out
+	case reflect.Struct:
+		t := v.Type()
+		out := reflect.New(t).Elem()
+		out.Set(v)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if f.Tag.Get("verbose") == "redact" {
+				if f.Type.Kind() == reflect.String {
+					out.Field(i).SetString(mask)
+				} else {
+					out.Field(i).Set(reflect.Zero(f.Type))
+				}
+				continue
+			}
+			out.Field(i).Set(redactValue(v.Field(i), mask, depth))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i), mask, depth))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i), mask, depth))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), redactValue(iter.Value(), mask, depth))
+		}
+		return out
+	}
+	return v
+}
+
+// redactTypes caches whether a type can hold a field tagged `verbose:"redact"`.
+var redactTypes sync.Map
+
+// hasRedactTag reports whether values of type t may contain a tagged field. Interfaces are assumed to, since the
+// dynamic value is only known at run time.
+func hasRedactTag(t reflect.Type) bool {
+	if has, ok := redactTypes.Load(t); ok {
+		return has.(bool)
+	}
+	has := typeHasRedactTag(t, map[reflect.Type]bool{})
+	redactTypes.Store(t, has)
+	return has
+}
+
+func typeHasRedactTag(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return typeHasRedactTag(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.IsExported() && (f.Tag.Get("verbose") == "redact" || typeHasRedactTag(f.Type, seen)) {
+				return true
+			}
+		}
+	}
+	return false
+}
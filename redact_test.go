@@ -0,0 +1,101 @@
+package verbose
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	s := Secret("hunter2")
+	for _, format := range []string{"%v", "%s", "%x", "%+v", "%#v", "%q", "%10s"} {
+		if got := fmt.Sprintf(format, s); strings.Contains(got, "hunter2") || !strings.Contains(got, DefaultMask) {
+			t.Errorf("Sprintf(%q, Secret) = %q", format, got)
+		}
+	}
+	if string(s) != "hunter2" {
+		t.Errorf("string(Secret) = %q, want the real value", string(s))
+	}
+}
+
+func TestSecret_StructField(t *testing.T) {
+	exported := struct {
+		User string
+		Pw   Secret
+	}{"bob", "hunter2"}
+	for _, format := range []string{"%v", "%+v", "%#v"} {
+		if got := fmt.Sprintf(format, exported); strings.Contains(got, "hunter2") {
+			t.Errorf("Sprintf(%q) of an exported Secret field = %q", format, got)
+		}
+	}
+	// The documented limitation: fmt doesn't call methods of unexported fields.
+	unexported := struct {
+		user string
+		pw   Secret
+	}{"bob", "hunter2"}
+	if got := fmt.Sprintf("%v", unexported); got != "{bob hunter2}" {
+		t.Errorf("Sprintf of an unexported Secret field = %q, the doc says {bob hunter2}", got)
+	}
+}
+
+func TestRedactor(t *testing.T) {
+	r := NewRedactor()
+	if err := r.AddPattern(`card=(\d+)`); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"user=bob password=hunter2 db=main", "user=bob password=**** db=main"},
+		{`{"access_token": "abc.def", "n": 1}`, `{"access_token": "****", "n": 1}`},
+		{"Authorization: Bearer eyJhbGciOi", "Authorization: Bearer ****"},
+		{"PASSWD='x y z'", "PASSWD='****'"},
+		{"card=4111111111111111 ok", "card=**** ok"},
+		{"nothing to hide", "nothing to hide"},
+	}
+	for _, test := range tests {
+		if got := r.Redact(test.in); got != test.want {
+			t.Errorf("Redact(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+type login struct {
+	User     string
+	Password string `verbose:"redact"`
+	PIN      int    `verbose:"redact"`
+	Next     *login
+}
+
+func TestVerb_PrintjRedact(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.V = true
+	v.Redactor = NewRedactor()
+	data := login{User: "bob", Password: "hunter2", PIN: 1234, Next: &login{User: "amy", Password: "letmein"}}
+	v.Printj(map[string]any{"login": data, "token": "abc"})
+	got := buf.String()
+	for _, leak := range []string{"hunter2", "letmein", "1234", "abc"} {
+		if strings.Contains(got, leak) {
+			t.Errorf("Printj leaked %q:\n%s", leak, got)
+		}
+	}
+	if data.Password != "hunter2" || data.Next.Password != "letmein" {
+		t.Errorf("Printj modified its argument: %+v", data)
+	}
+}
+
+func TestVerb_PrintlnRedact(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.V = true
+	v.Redactor = NewRedactor()
+	v.Println("Query:", "host=db password=hunter2", Secret("s3cr3t"))
+	if got, want := buf.String(), "Query: host=db password=**** ****\n"; got != want {
+		t.Errorf("Println = %q, want %q", got, want)
+	}
+}
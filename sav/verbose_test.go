@@ -1,3 +1,6 @@
+//go:build ignore
+
+// An old copy of verbose_test.go, kept for reference.
 package verbose
 
 import (
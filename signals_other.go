@@ -0,0 +1,8 @@
+//go:build !unix
+
+package verbose
+
+// HandleSignals does nothing on systems without SIGUSR1 and SIGUSR2.
+func (v *Verb) HandleSignals() (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,41 @@
+//go:build unix
+
+package verbose
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// HandleSignals lets you switch verbose mode in a running process without restarting it.
+// SIGUSR1 turns verbose mode on and SIGUSR2 turns it off, and each change is logged to Out.
+// Call the returned function to stop handling the signals.
+//
+//	stop := verb.HandleSignals()
+//	defer stop()
+//
+// Then from a shell: kill -USR1 <pid>
+func (v *Verb) HandleSignals() (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(c, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for {
+			select {
+			case sig := <-c:
+				v.switchV(sig == syscall.SIGUSR1, sig.String()+" received")
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+//go:build unix
+
+package verbose
+
+import (
+	"bytes"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer that can be read while a Verb writes to it from another goroutine.
+type syncBuffer struct {
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return b.buf.String()
+}
+
+func TestVerb_HandleSignals(t *testing.T) {
+	var out syncBuffer
+	v := New(&out)
+	stop := v.HandleSignals()
+	defer stop()
+
+	// Print concurrently with the switch so the race detector can check the locking.
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				v.Println("tick")
+			}
+		}
+	}()
+	defer close(done)
+
+	for _, test := range []struct {
+		sig  syscall.Signal
+		want bool
+	}{
+		{syscall.SIGUSR1, true},
+		{syscall.SIGUSR2, false},
+	} {
+		syscall.Kill(syscall.Getpid(), test.sig)
+		deadline := time.Now().Add(2 * time.Second)
+		for v.Enabled() != test.want && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if v.Enabled() != test.want {
+			t.Fatalf("after %v Enabled() = %v, want %v", test.sig, v.Enabled(), test.want)
+		}
+	}
+	if got := out.String(); !strings.Contains(got, "user defined signal 1 received, verbose mode on") {
+		t.Errorf("change was not logged:\n%.300s", got)
+	}
+}
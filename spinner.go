@@ -31,7 +31,9 @@ type Spinner struct {
 	// Speed.  1000ms / <speed> will be how long the spinner will display each segment. Default is 10.
 	Speed int
 	// Quit is the channel used when you're done with the spinner.  spinner.Quit <- true
-	Quit  chan bool
+	Quit chan bool
+	// Clock times the frames. nil uses the system clock.
+	Clock Clock
 	text  string
 	n     int
 	Chars []string
@@ -52,6 +54,7 @@ func (v *Verb) Spin(I ...int) {
 	}
 	spnr := NewSpinner("Working:", "stderr", I[0])
 	spnr.Speed = 7
+	spnr.Clock = v.Clock
 	go spnr.Start()
 	quit := <-v.Quit
 	fmt.Println("Done")
@@ -68,10 +71,17 @@ func (s *Spinner) Start() {
 		default:
 			s.Spin()
 		}
-		time.Sleep(time.Duration(1000/s.Speed) * time.Millisecond)
+		s.clock().Sleep(time.Duration(1000/s.Speed) * time.Millisecond)
 	}
 }
 
+func (s *Spinner) clock() Clock {
+	if s.Clock != nil {
+		return s.Clock
+	}
+	return SystemClock
+}
+
 // Spinner implements terminal spinner attached to *os.File which usually
 // either stdout or stderr. Both zero and nil values are valid and are no-op.
 // If spinner created on an *os.File that is not attached to the terminal,
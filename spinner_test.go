@@ -1,48 +1,33 @@
 package verbose
 
 import (
+	"io"
 	"os"
 	"testing"
-	"time"
-
-	"github.com/briandowns/spinner"
 )
 
-func TestSpin(t *testing.T) {
-	quit := make(chan bool)
-	v := New()
-
-	go func() {
-		time.Sleep(1 * time.Second)
-		quit <- true
-	}()
-
-	// Redirect stdout to a buffer
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	v.Spin(quit)
-
-	// Restore stdout
+func TestSpinner(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Spinner{F: w, text: "Working:", Chars: []string{"|", "/", "-", "\\"}}
+	s.Spin()
+	s.Spin()
+	s.Clear()
 	w.Close()
-	os.Stdout = old
-
-	// Read from the buffer and check the output
-	out := make(chan string)
-	go func() {
-		var buf bytes.Buffer
-		io.Copy(&buf, r)
-		out <- buf.String()
-	}()
+	got, _ := io.ReadAll(r)
+	expected := "\rWorking: / \rWorking: - \r           \b\b\b\b\b\b\b\b\b\b\b\r"
+	if string(got) != expected {
+		t.Errorf("Spin() = %q, want %q", got, expected)
+	}
+}
 
-	expected := "  Working: |  Working: /  Working: -  Working: \\"
-	select {
-	case got := <-out:
-		if got != expected {
-			t.Errorf("Spin() = %q, want %q", got, expected)
-		}
-	case <-time.After(2 * time.Second):
-		t.Errorf("Spin() took too long")
+func TestNewSpinner_NotATerminal(t *testing.T) {
+	// Test output is not a terminal, so the spinner does nothing.
+	if s := NewSpinner("Working:", "stderr", 0); s.F != nil {
+		t.Errorf("NewSpinner returned a spinner on %v", s.F.Name())
 	}
+	var s *Spinner
+	s.Clear()
 }
@@ -0,0 +1,199 @@
+package verbose
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Entry is a single message written by a Verb.
+type Entry struct {
+	Time time.Time
+	// File and Line are the call site, File is the base name. Printj entries have no call site.
+	File string
+	Line int
+	// Msg is the message after redaction, without the date and line prefix.
+	Msg string
+	// Text is the message as it was written, including the prefix.
+	Text string
+}
+
+// Subscription receives every message a Verb writes. A subscriber that falls behind loses messages instead of
+// slowing down the program; Dropped says how many.
+type Subscription struct {
+	// C delivers the messages.
+	C <-chan Entry
+
+	c       chan Entry
+	dropped atomic.Int64
+	verb    *Verb
+}
+
+type hub struct {
+	subs  map[*Subscription]struct{}
+	hooks map[*func(Entry)]struct{}
+}
+
+// getHub returns the Verb's hub, creating it. mu must be held.
+func (v *Verb) getHub() *hub {
+	if v.hub == nil {
+		v.hub = &hub{subs: map[*Subscription]struct{}{}, hooks: map[*func(Entry)]struct{}{}}
+	}
+	return v.hub
+}
+
+// dropHub removes the hub once nothing is listening. mu must be held.
+func (v *Verb) dropHub() {
+	if h := v.hub; h != nil && len(h.subs) == 0 && len(h.hooks) == 0 {
+		v.hub = nil
+	}
+}
+
+// Subscribe starts delivering the Verb's messages to a new Subscription buffering up to size of them.
+// Only messages that are actually written are delivered, so nothing arrives while verbose mode is off.
+// Call Close when done.
+func (v *Verb) Subscribe(size int) *Subscription {
+	if size < 1 {
+		size = 1
+	}
+	c := make(chan Entry, size)
+	s := &Subscription{C: c, c: c, verb: v}
+	mu.Lock()
+	v.getHub().subs[s] = struct{}{}
+	mu.Unlock()
+	return s
+}
+
+// AddHook calls f with every message the Verb writes, as it is written. f is called with the Verb's lock held, so it
+// must be quick and must not print. Unlike a Subscription nothing is dropped, which makes hooks the way to capture
+// output in tests. remove stops the calls.
+func (v *Verb) AddHook(f func(Entry)) (remove func()) {
+	p := &f
+	mu.Lock()
+	v.getHub().hooks[p] = struct{}{}
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if v.hub != nil {
+			delete(v.hub.hooks, p)
+			v.dropHub()
+		}
+	}
+}
+
+// Dropped returns the number of messages lost since the last call to Dropped because C was full.
+func (s *Subscription) Dropped() int64 {
+	return s.dropped.Swap(0)
+}
+
+// Close stops the subscription and closes C.
+func (s *Subscription) Close() {
+	mu.Lock()
+	defer mu.Unlock()
+	h := s.verb.hub
+	if h == nil {
+		return
+	}
+	if _, ok := h.subs[s]; ok {
+		delete(h.subs, s)
+		close(s.c)
+	}
+	s.verb.dropHub()
+}
+
+// publish passes e to the hooks and hands it to every subscriber without blocking. mu must be held.
+func (v *Verb) publish(e Entry) {
+	if v.hub == nil {
+		return
+	}
+	for f := range v.hub.hooks {
+		(*f)(e)
+	}
+	for s := range v.hub.subs {
+		select {
+		case s.c <- e:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// streamKeepAlive is how often an idle stream sends a comment so proxies don't close it.
+const streamKeepAlive = 15 * time.Second
+
+// StreamHandler returns an http.Handler that streams the Verb's messages to the browser as Server-Sent Events.
+// Each client can filter what it gets with query parameters:
+//
+//	q     only messages containing this text
+//	file  only messages from this file, or file:line
+//
+// A client that can't keep up loses messages rather than blocking Print; it is sent a "dropped" event with the count.
+// The handler shows whatever verbose writes, so only mount it where you would let people read the log.
+//
+//	http.Handle("/debug/verbose/stream", verb.StreamHandler())
+//	curl -N 'localhost:6060/debug/verbose/stream?q=Query'
+func (v *Verb) StreamHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		q := r.URL.Query().Get("q")
+		file := r.URL.Query().Get("file")
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		sub := v.Subscribe(256)
+		defer sub.Close()
+		tick := time.NewTicker(streamKeepAlive)
+		defer tick.Stop()
+		var id int64
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-tick.C:
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+			case e, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if n := sub.Dropped(); n > 0 {
+					fmt.Fprintf(w, "event: dropped\ndata: %d\n\n", n)
+				}
+				if !streamMatch(e, q, file) {
+					continue
+				}
+				id++
+				fmt.Fprintf(w, "id: %d\n", id)
+				for _, line := range strings.Split(strings.TrimSuffix(e.Text, "\n"), "\n") {
+					fmt.Fprintf(w, "data: %s\n", line)
+				}
+				if _, err := fmt.Fprint(w, "\n"); err != nil {
+					return
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	})
+}
+
+// streamMatch reports whether e passes a client's filters.
+func streamMatch(e Entry, q, file string) bool {
+	if q != "" && !strings.Contains(e.Msg, q) {
+		return false
+	}
+	if file != "" && file != e.File && file != fmt.Sprintf("%s:%d", e.File, e.Line) {
+		return false
+	}
+	return true
+}
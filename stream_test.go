@@ -0,0 +1,109 @@
+package verbose
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerb_Subscribe(t *testing.T) {
+	needsVerbose(t)
+	v := New(io.Discard)
+	v.V = true
+	sub := v.Subscribe(2)
+	v.Println("one")
+	v.Println("two")
+	v.Println("three")
+	if e := <-sub.C; e.Msg != "one\n" || e.File != "stream_test.go" || e.Line == 0 {
+		t.Errorf("first entry = %+v", e)
+	}
+	if n := sub.Dropped(); n != 1 {
+		t.Errorf("Dropped() = %d, want 1", n)
+	}
+	sub.Close()
+	if _, ok := <-sub.C; !ok {
+		t.Error("buffered entry lost on Close")
+	}
+	if _, ok := <-sub.C; ok {
+		t.Error("C not closed")
+	}
+	v.Println("after close")
+}
+
+func TestVerb_AddHook(t *testing.T) {
+	needsVerbose(t)
+	v := New(io.Discard)
+	v.V = true
+	var got []string
+	remove := v.AddHook(func(e Entry) { got = append(got, e.Msg) })
+	sub := v.Subscribe(1)
+	for i := 0; i < 3; i++ {
+		v.Println("hooked")
+	}
+	remove()
+	v.Println("removed")
+	if len(got) != 3 {
+		t.Errorf("hook got %q, want three messages", got)
+	}
+	if v.hub == nil {
+		t.Error("removing the hook dropped the subscription")
+	}
+	sub.Close()
+	if v.hub != nil {
+		t.Error("hub kept with nothing listening")
+	}
+}
+
+func TestVerb_StreamHandler(t *testing.T) {
+	needsVerbose(t)
+	v := New(io.Discard)
+	v.V = true
+	srv := httptest.NewServer(v.StreamHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?q=Query")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// The subscription starts once the headers are sent, so wait for it before printing.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		ready := v.hub != nil
+		mu.Unlock()
+		if ready || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	v.Println("skipped")
+	v.Println("Query:", "select 1")
+
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+		close(lines)
+	}()
+	var got []string
+	for line := range lines {
+		if line == "" {
+			break
+		}
+		got = append(got, line)
+	}
+	if want := []string{"id: 1", "data: Query: select 1"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("event = %q, want %q", got, want)
+	}
+}
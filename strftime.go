@@ -0,0 +1,353 @@
+package verbose
+
+import (
+	"strings"
+	"time"
+)
+
+// Strftime formats t the way date(1) does with format, using the C locale. Unlike TimeFormatStr, which can only
+// translate directives that have a Go layout equivalent, it handles the whole GNU directive set:
+//
+//	%a %A %b %B %h    weekday and month names, abbreviated and full
+//	%c %D %F %r %R %T %x %X    composites: "%a %b %e %H:%M:%S %Y", "%m/%d/%y", "%Y-%m-%d", "%I:%M:%S %p",
+//	                           "%H:%M", "%H:%M:%S", "%m/%d/%y" and "%H:%M:%S"
+//	%C %y %Y %G %g    century, year and ISO 8601 week-based year
+//	%m %d %e %j %q    month, day of month (%e space padded), day of year, quarter
+//	%H %k %I %l %M %S %N    hour (%k and %l space padded), minute, second, nanoseconds (%3N milliseconds)
+//	%p %P             AM/PM and am/pm
+//	%s                seconds since 1970-01-01 UTC
+//	%u %w %U %W %V    weekday (1-7 from Monday, 0-6 from Sunday) and week numbers (from Sunday, from Monday, ISO)
+//	%z %:z %::z %:::z %Z    numeric zone +hhmm, +hh:mm, +hh:mm:ss, shortest, and the zone abbreviation, or
+//	                        +hhmm for a zone without one
+//	%n %t %%          newline, tab and %
+//
+// After the % a flag may follow: - for no padding, _ to pad with spaces, 0 to pad with zeros, ^ for upper case and
+// # for the opposite case, then a field width, as in %-d, %_H or %10Y. Unknown directives are copied as they are.
+func Strftime(format string, t time.Time) string {
+	return string(AppendStrftime(nil, format, t))
+}
+
+// AppendStrftime is like Strftime but appends to b and returns the extended buffer.
+func AppendStrftime(b []byte, format string, t time.Time) []byte {
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c != '%' {
+			b = append(b, c)
+			continue
+		}
+		start := i
+		i++
+		var flag byte
+		for i < len(format) && strings.IndexByte("-_0^#", format[i]) >= 0 {
+			flag = format[i]
+			i++
+		}
+		width := -1
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			if width < 0 {
+				width = 0
+			}
+			width = width*10 + int(format[i]-'0')
+			i++
+		}
+		for i < len(format) && (format[i] == 'E' || format[i] == 'O') {
+			i++
+		}
+		colons := 0
+		for i < len(format) && format[i] == ':' {
+			colons++
+			i++
+		}
+		if i >= len(format) {
+			return append(b, format[start:]...)
+		}
+		d := format[i]
+		if colons > 0 && d != 'z' {
+			b = append(b, format[start:i+1]...)
+			continue
+		}
+		b = appendDirective(b, d, flag, width, colons, t, format[start:i+1])
+	}
+	return b
+}
+
+// appendDirective appends the conversion d with its flag, width (-1 if none) and colons. raw is the whole directive,
+// copied when d is unknown.
+func appendDirective(b []byte, d, flag byte, width, colons int, t time.Time, raw string) []byte {
+	num := func(n, w int, pad byte) []byte {
+		switch flag {
+		case '-':
+			pad = 0
+		case '_':
+			pad = ' '
+		case '0':
+			pad = '0'
+		}
+		if width >= 0 {
+			w = width
+		}
+		return appendPadded(b, n, w, pad)
+	}
+	text := func(s string) []byte {
+		switch flag {
+		case '^':
+			s = strings.ToUpper(s)
+		case '#':
+			if d == 'p' || d == 'Z' {
+				s = strings.ToLower(s)
+			} else {
+				s = strings.ToUpper(s)
+			}
+		}
+		pad := byte(' ')
+		if flag == '0' {
+			pad = '0'
+		}
+		for n := len(s); n < width; n++ {
+			b = append(b, pad)
+		}
+		return append(b, s...)
+	}
+	composite := func(f string) []byte {
+		return text(string(AppendStrftime(nil, f, t)))
+	}
+
+	switch d {
+	case 'a':
+		return text(t.Weekday().String()[:3])
+	case 'A':
+		return text(t.Weekday().String())
+	case 'b', 'h':
+		return text(t.Month().String()[:3])
+	case 'B':
+		return text(t.Month().String())
+	case 'c':
+		return composite("%a %b %e %H:%M:%S %Y")
+	case 'C':
+		return num(floorDiv(t.Year(), 100), 2, '0')
+	case 'd':
+		return num(t.Day(), 2, '0')
+	case 'D', 'x':
+		return composite("%m/%d/%y")
+	case 'e':
+		return num(t.Day(), 2, ' ')
+	case 'F':
+		return composite("%Y-%m-%d")
+	case 'g':
+		y, _ := t.ISOWeek()
+		return num(floorMod(y, 100), 2, '0')
+	case 'G':
+		y, _ := t.ISOWeek()
+		return num(y, 4, '0')
+	case 'H':
+		return num(t.Hour(), 2, '0')
+	case 'I':
+		return num(hour12(t), 2, '0')
+	case 'j':
+		return num(t.YearDay(), 3, '0')
+	case 'k':
+		return num(t.Hour(), 2, ' ')
+	case 'l':
+		return num(hour12(t), 2, ' ')
+	case 'm':
+		return num(int(t.Month()), 2, '0')
+	case 'M':
+		return num(t.Minute(), 2, '0')
+	case 'n':
+		return append(b, '\n')
+	case 'N':
+		digits := 9
+		if width > 0 && width < 9 {
+			digits = width
+		}
+		ns := t.Nanosecond()
+		for i := digits; i < 9; i++ {
+			ns /= 10
+		}
+		return appendPadded(b, ns, digits, '0')
+	case 'p':
+		return text(ampm(t))
+	case 'P':
+		return text(strings.ToLower(ampm(t)))
+	case 'q':
+		return num((int(t.Month())+2)/3, 1, '0')
+	case 'r':
+		return composite("%I:%M:%S %p")
+	case 'R':
+		return composite("%H:%M")
+	case 's':
+		return num64(b, t.Unix(), flag, width)
+	case 'S':
+		return num(t.Second(), 2, '0')
+	case 't':
+		return append(b, '\t')
+	case 'T', 'X':
+		return composite("%H:%M:%S")
+	case 'u':
+		wd := int(t.Weekday())
+		if wd == 0 {
+			wd = 7
+		}
+		return num(wd, 1, '0')
+	case 'U':
+		return num((t.YearDay()+6-int(t.Weekday()))/7, 2, '0')
+	case 'V':
+		_, w := t.ISOWeek()
+		return num(w, 2, '0')
+	case 'w':
+		return num(int(t.Weekday()), 1, '0')
+	case 'W':
+		return num((t.YearDay()+6-(int(t.Weekday())+6)%7)/7, 2, '0')
+	case 'y':
+		return num(floorMod(t.Year(), 100), 2, '0')
+	case 'Y':
+		return num(t.Year(), 4, '0')
+	case 'z':
+		return appendZone(b, t, colons)
+	case 'Z':
+		name, _ := t.Zone()
+		if name == "" {
+			name = string(appendZone(nil, t, 0))
+		}
+		return text(name)
+	case '%':
+		return append(b, '%')
+	}
+	return append(b, raw...)
+}
+
+// appendPadded appends n padded to width w with pad, or not padded when pad is 0.
+func appendPadded(b []byte, n, w int, pad byte) []byte {
+	if n < 0 {
+		b = append(b, '-')
+		n = -n
+		w--
+	}
+	var tmp [20]byte
+	i := len(tmp)
+	for {
+		i--
+		tmp[i] = byte('0' + n%10)
+		n /= 10
+		if n == 0 {
+			break
+		}
+	}
+	if pad != 0 {
+		for l := len(tmp) - i; l < w; l++ {
+			b = append(b, pad)
+		}
+	}
+	return append(b, tmp[i:]...)
+}
+
+// num64 appends the seconds of %s, which are only padded when a width is given.
+func num64(b []byte, n int64, flag byte, width int) []byte {
+	pad := byte('0')
+	if flag == '_' {
+		pad = ' '
+	}
+	if flag == '-' || width < 0 {
+		width = 0
+	}
+	return appendPadded(b, int(n), width, pad)
+}
+
+// appendZone appends the zone offset for %z, %:z, %::z and %:::z.
+func appendZone(b []byte, t time.Time, colons int) []byte {
+	_, off := t.Zone()
+	sign := byte('+')
+	if off < 0 {
+		sign = '-'
+		off = -off
+	}
+	h, m, s := off/3600, off/60%60, off%60
+	b = append(b, sign)
+	b = appendPadded(b, h, 2, '0')
+	switch colons {
+	case 0:
+		return appendPadded(b, m, 2, '0')
+	case 1:
+		return appendPadded(append(b, ':'), m, 2, '0')
+	case 2:
+		b = appendPadded(append(b, ':'), m, 2, '0')
+		return appendPadded(append(b, ':'), s, 2, '0')
+	}
+	if m != 0 || s != 0 {
+		b = appendPadded(append(b, ':'), m, 2, '0')
+	}
+	if s != 0 {
+		b = appendPadded(append(b, ':'), s, 2, '0')
+	}
+	return b
+}
+
+func hour12(t time.Time) int {
+	h := t.Hour() % 12
+	if h == 0 {
+		h = 12
+	}
+	return h
+}
+
+func ampm(t time.Time) string {
+	if t.Hour() < 12 {
+		return "AM"
+	}
+	return "PM"
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b < 0 {
+		q--
+	}
+	return q
+}
+
+func floorMod(a, b int) int {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+	return m
+}
+
+// translatable reports whether TimeFormatStr turns format into a Go layout that prints the same thing, which is
+// only when it uses nothing but the directives TimeFormatStr knows, without flags or widths.
+func translatable(format string) bool {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		if i >= len(format) || strings.IndexByte("YmDBbdjAaHIMSNFTZP", format[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// legacyStrftime rewrites the %N and %P in format to print what TimeFormatStr makes of them, .000 and PM, so a format
+// that needs Strftime for another directive prints them the same as one that doesn't. A %N after a dot or comma is
+// already the same.
+func legacyStrftime(format string) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 >= len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		switch s := b.String(); {
+		case format[i] == 'N' && !strings.HasSuffix(s, ".") && !strings.HasSuffix(s, ","):
+			b.WriteString(".%3N")
+		case format[i] == 'P':
+			b.WriteString("%p")
+		default:
+			b.WriteByte('%')
+			b.WriteByte(format[i])
+		}
+	}
+	return b.String()
+}
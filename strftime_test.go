@@ -0,0 +1,67 @@
+package verbose
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestStrftime(t *testing.T) {
+	// Expected values are from GNU date 9.1.
+	nst := time.FixedZone("NST", -(3*3600 + 30*60))
+	morning := time.Date(2021, 1, 3, 9, 5, 7, 123456789, nst)
+	ist := time.FixedZone("IST", 5*3600+30*60)
+	night := time.Date(2021, 12, 31, 23, 59, 58, 0, ist)
+	tests := []struct {
+		t      time.Time
+		format string
+		want   string
+	}{
+		{morning, "%a %A %b %B %h", "Sun Sunday Jan January Jan"},
+		{morning, "%c", "Sun Jan  3 09:05:07 2021"},
+		{morning, "%C %y %Y %G %g", "20 21 2021 2020 20"},
+		{morning, "%D %F %x", "01/03/21 2021-01-03 01/03/21"},
+		{morning, "%e|%-e|%_d|%0e", " 3|3| 3|03"},
+		{morning, "%H %k %I %l %M %S", "09  9 09  9 05 07"},
+		{morning, "%N %3N %6N", "123456789 123 123456"},
+		{morning, "%p %P %^a %#p %#Z %^B", "AM am SUN am nst JANUARY"},
+		{morning, "%q", "1"},
+		{morning, "%r %R %T %X", "09:05:07 AM 09:05 09:05:07 09:05:07"},
+		{morning, "%s", "1609677307"},
+		{morning, "%u %w %U %W %V", "7 0 01 00 53"},
+		{morning, "%z %:z %::z %:::z %Z", "-0330 -03:30 -03:30:00 -03:30 NST"},
+		{morning, "%j %-j %5Y", "003 3 02021"},
+		{morning, "100%% %Q %n%t|", "100% %Q \n\t|"},
+		{morning, "%-m/%-d %_H", "1/3  9"},
+		{night, "%I %l %p %P %r", "11 11 PM pm 11:59:58 PM"},
+		{night, "%:::z %12s", "+05:30 001640975398"},
+		{night, "%_5d|%-5d|%05e", "   31|31|00031"},
+		{night, "x%", "x%"},
+	}
+	for _, tt := range tests {
+		if got := Strftime(tt.format, tt.t); got != tt.want {
+			t.Errorf("Strftime(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestNew_Strftime(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	date, epoch := "%Y-%m-%d", "%s.%3N" // not constants, or vet takes New for a printf wrapper
+	v := New(&buf, date)
+	if v.Strftime != "" || v.Dformat != "2006-01-02" {
+		t.Errorf("translatable format: Strftime %q, Dformat %q", v.Strftime, v.Dformat)
+	}
+	v = New(&buf, epoch)
+	if v.Strftime != "%s.%3N" {
+		t.Errorf("Strftime = %q", v.Strftime)
+	}
+	v.V = true
+	v.Delimeter = "|"
+	v.Clock = NewStepClock(time.Unix(1609677307, 123456789), 0)
+	v.Println("epoch")
+	if got := buf.String(); got != "1609677307.123|epoch\n" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,524 @@
+package verbose
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeLayout is a compiled date(1) format that formats like Strftime and parses what it formats back, like strptime.
+// Names are read in English whatever the locale, case insensitively.
+type TimeLayout struct {
+	format string
+	parts  []timePart
+}
+
+// timePart is a literal, when d is 0, or a directive.
+type timePart struct {
+	lit    string
+	d      byte
+	flag   byte
+	width  int
+	colons int
+	// reserve is how many digits the directives straight after this one need, so %s%3N leaves the milliseconds.
+	reserve int
+}
+
+// CompileTime compiles a date(1) format for formatting and parsing. Directives Strftime doesn't know are an error.
+func CompileTime(format string) (*TimeLayout, error) {
+	l := &TimeLayout{format: format}
+	if err := l.compile(format); err != nil {
+		return nil, err
+	}
+	for i := len(l.parts) - 2; i >= 0; i-- {
+		if n := l.parts[i+1].digits(); n > 0 {
+			l.parts[i].reserve = n + l.parts[i+1].reserve
+		}
+	}
+	return l, nil
+}
+
+// digits returns the number of digits the directive always takes, or 0 if that varies or it isn't a number.
+func (p timePart) digits() int {
+	if p.d == 0 || p.flag == '-' {
+		return 0
+	}
+	if p.width > 0 {
+		return p.width
+	}
+	switch p.d {
+	case 'u', 'w', 'q':
+		return 1
+	case 'C', 'd', 'g', 'H', 'I', 'm', 'M', 'S', 'U', 'V', 'W', 'y':
+		return 2
+	case 'j':
+		return 3
+	case 'Y', 'G':
+		return 4
+	case 'N':
+		return 9
+	}
+	return 0
+}
+
+// MustCompileTime is like CompileTime but panics if the format has an unknown directive.
+func MustCompileTime(format string) *TimeLayout {
+	l, err := CompileTime(format)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// ParseTime parses value, written with the date(1) format, like time.Parse: without a zone in the value the time is
+// UTC.
+func ParseTime(format, value string) (time.Time, error) {
+	l, err := CompileTime(format)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return l.Parse(value)
+}
+
+// String returns the format the layout was compiled from.
+func (l *TimeLayout) String() string {
+	return l.format
+}
+
+// Format formats t, the same as Strftime.
+func (l *TimeLayout) Format(t time.Time) string {
+	return Strftime(l.format, t)
+}
+
+// AppendFormat is like Format but appends to b and returns the extended buffer.
+func (l *TimeLayout) AppendFormat(b []byte, t time.Time) []byte {
+	return AppendStrftime(b, l.format, t)
+}
+
+// composites are the directives that stand for a longer format.
+var composites = map[byte]string{
+	'c': "%a %b %e %H:%M:%S %Y",
+	'D': "%m/%d/%y",
+	'x': "%m/%d/%y",
+	'F': "%Y-%m-%d",
+	'r': "%I:%M:%S %p",
+	'R': "%H:%M",
+	'T': "%H:%M:%S",
+	'X': "%H:%M:%S",
+}
+
+func (l *TimeLayout) literal(s string) {
+	if n := len(l.parts); n > 0 && l.parts[n-1].d == 0 {
+		l.parts[n-1].lit += s
+		return
+	}
+	l.parts = append(l.parts, timePart{lit: s})
+}
+
+func (l *TimeLayout) compile(format string) error {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			l.literal(format[i : i+1])
+			continue
+		}
+		start := i
+		p := timePart{width: -1}
+		for i++; i < len(format) && strings.IndexByte("-_0^#", format[i]) >= 0; i++ {
+			p.flag = format[i]
+		}
+		for ; i < len(format) && format[i] >= '0' && format[i] <= '9'; i++ {
+			if p.width < 0 {
+				p.width = 0
+			}
+			p.width = p.width*10 + int(format[i]-'0')
+		}
+		for ; i < len(format) && (format[i] == 'E' || format[i] == 'O'); i++ {
+		}
+		for ; i < len(format) && format[i] == ':'; i++ {
+			p.colons++
+		}
+		if i >= len(format) {
+			return fmt.Errorf("verbose: format %q ends in an incomplete directive %q", format, format[start:])
+		}
+		p.d = format[i]
+		switch {
+		case p.colons > 0 && p.d != 'z':
+			return fmt.Errorf("verbose: unknown directive %q in format %q", format[start:i+1], format)
+		case p.d == '%':
+			l.literal("%")
+		case composites[p.d] != "":
+			if err := l.compile(composites[p.d]); err != nil {
+				return err
+			}
+		case strings.IndexByte("aAbBhCdeGgHIjklmMnNpPqsStuUVwWyYzZ", p.d) >= 0:
+			l.parts = append(l.parts, p)
+		default:
+			return fmt.Errorf("verbose: unknown directive %q in format %q", format[start:i+1], format)
+		}
+	}
+	return nil
+}
+
+// Parse parses value like time.Parse: without a zone in the value the time is UTC.
+func (l *TimeLayout) Parse(value string) (time.Time, error) {
+	return l.ParseInLocation(value, time.UTC)
+}
+
+// ParseInLocation parses value like time.ParseInLocation: without a zone in the value the time is in loc, and a %Z
+// abbreviation is looked up in loc first.
+func (l *TimeLayout) ParseInLocation(value string, loc *time.Location) (time.Time, error) {
+	t, n, err := l.parsePrefix(value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if n < len(value) {
+		return time.Time{}, fmt.Errorf("verbose: parsing %q as %q: extra text %q", value, l.format, value[n:])
+	}
+	return t, nil
+}
+
+// parsed collects the fields read by parsePrefix.
+type parsed struct {
+	year, month, day, yday     int
+	hour, min, sec, nsec       int
+	century, yy                int
+	pm, hasPM, hour12, hasYday bool
+	hasYear, hasCentury, hasYY bool
+	epoch                      int64
+	hasEpoch                   bool
+	zone                       *time.Location
+	zoneName                   string
+}
+
+var errTimeSyntax = errors.New("doesn't match")
+
+// parsePrefix parses a time at the start of value and returns it with the number of bytes read.
+func (l *TimeLayout) parsePrefix(value string, loc *time.Location) (time.Time, int, error) {
+	fail := func(i int, why string) (time.Time, int, error) {
+		return time.Time{}, 0, fmt.Errorf("verbose: parsing %q as %q: %s at %q", value, l.format, why, value[i:])
+	}
+	p := parsed{month: 1, day: 1}
+	i := 0
+	for _, part := range l.parts {
+		if part.d == 0 {
+			for j := 0; j < len(part.lit); j++ {
+				c := part.lit[j]
+				if c == ' ' {
+					// A space matches any run of spaces, so space padded fields line up.
+					if i >= len(value) || value[i] != ' ' {
+						return fail(i, "expected a space")
+					}
+					for i < len(value) && value[i] == ' ' {
+						i++
+					}
+					continue
+				}
+				if i >= len(value) || value[i] != c {
+					return fail(i, fmt.Sprintf("expected %q", c))
+				}
+				i++
+			}
+			continue
+		}
+		n, err := p.field(part, value[i:])
+		if err != nil {
+			return fail(i, fmt.Sprintf("%%%c %v", part.d, err))
+		}
+		i += n
+	}
+	t, err := p.time(loc)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("verbose: parsing %q as %q: %v", value, l.format, err)
+	}
+	return t, i, nil
+}
+
+// field reads one directive from the start of s and returns the number of bytes read.
+func (p *parsed) field(part timePart, s string) (int, error) {
+	num := func(max int, signed bool) (int, int, error) {
+		i := 0
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		neg := false
+		if signed && i < len(s) && (s[i] == '-' || s[i] == '+') {
+			neg = s[i] == '-'
+			i++
+		}
+		if part.width > 0 {
+			max = part.width
+		}
+		start, n := i, 0
+		if max <= 0 {
+			// Unbounded, like %s: leave the digits the directives after it need.
+			j := start
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			if max = j - start - part.reserve; max <= 0 {
+				return 0, 0, errTimeSyntax
+			}
+		}
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' && i-start < max {
+			n = n*10 + int(s[i]-'0')
+			i++
+		}
+		if i == start {
+			return 0, 0, errTimeSyntax
+		}
+		if neg {
+			n = -n
+		}
+		return n, i, nil
+	}
+	ranged := func(dst *int, max, lo, hi int) (int, error) {
+		n, i, err := num(max, false)
+		if err != nil {
+			return 0, err
+		}
+		if n < lo || n > hi {
+			return 0, fmt.Errorf("value %d out of range", n)
+		}
+		*dst = n
+		return i, nil
+	}
+	var ignore int
+	switch part.d {
+	case 'a', 'A':
+		return matchName(s, weekdayNames[:], &ignore)
+	case 'b', 'B', 'h':
+		var m int
+		i, err := matchName(s, monthNames[:], &m)
+		p.month = m + 1
+		return i, err
+	case 'C':
+		p.hasCentury = true
+		return ranged(&p.century, 2, 0, 99)
+	case 'd', 'e':
+		return ranged(&p.day, 2, 1, 31)
+	case 'G':
+		_, i, err := num(4, true)
+		return i, err
+	case 'g', 'U', 'W', 'V':
+		return ranged(&ignore, 2, 0, 99)
+	case 'u', 'w', 'q':
+		return ranged(&ignore, 1, 0, 7)
+	case 'H', 'k':
+		return ranged(&p.hour, 2, 0, 24)
+	case 'I', 'l':
+		p.hour12 = true
+		return ranged(&p.hour, 2, 1, 12)
+	case 'j':
+		p.hasYday = true
+		return ranged(&p.yday, 3, 1, 366)
+	case 'm':
+		return ranged(&p.month, 2, 1, 12)
+	case 'M':
+		return ranged(&p.min, 2, 0, 59)
+	case 'S':
+		return ranged(&p.sec, 2, 0, 60)
+	case 'n', 't':
+		i := 0
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t' || s[i] == '\n') {
+			i++
+		}
+		return i, nil
+	case 'N':
+		max := 9
+		if part.width > 0 && part.width < 9 {
+			max = part.width
+		}
+		i, n := 0, 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' && i < max {
+			n = n*10 + int(s[i]-'0')
+			i++
+		}
+		if i == 0 {
+			return 0, errTimeSyntax
+		}
+		for j := i; j < 9; j++ {
+			n *= 10
+		}
+		p.nsec = n
+		return i, nil
+	case 'p', 'P':
+		if len(s) < 2 {
+			return 0, errTimeSyntax
+		}
+		switch strings.ToUpper(s[:2]) {
+		case "AM":
+		case "PM":
+			p.pm = true
+		default:
+			return 0, errTimeSyntax
+		}
+		p.hasPM = true
+		return 2, nil
+	case 's':
+		n, i, err := num(0, true)
+		p.epoch, p.hasEpoch = int64(n), true
+		return i, err
+	case 'y':
+		p.hasYY = true
+		return ranged(&p.yy, 2, 0, 99)
+	case 'Y':
+		n, i, err := num(4, true)
+		p.year, p.hasYear = n, true
+		return i, err
+	case 'z':
+		return p.offset(s)
+	case 'Z':
+		if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+			return p.offset(s)
+		}
+		i := 0
+		for i < len(s) && (s[i] >= 'A' && s[i] <= 'Z' || s[i] >= 'a' && s[i] <= 'z') {
+			i++
+		}
+		if i < 2 {
+			return 0, errTimeSyntax
+		}
+		p.zoneName = s[:i]
+		return i, nil
+	}
+	return 0, errTimeSyntax
+}
+
+// offset reads a numeric zone: Z, +hh, +hhmm, +hh:mm or +hh:mm:ss.
+func (p *parsed) offset(s string) (int, error) {
+	if strings.HasPrefix(s, "Z") || strings.HasPrefix(s, "z") {
+		p.zone = time.UTC
+		return 1, nil
+	}
+	if len(s) < 3 || (s[0] != '+' && s[0] != '-') {
+		return 0, errTimeSyntax
+	}
+	digits := func(s string) (int, bool) {
+		if len(s) < 2 || s[0] < '0' || s[0] > '9' || s[1] < '0' || s[1] > '9' {
+			return 0, false
+		}
+		return int(s[0]-'0')*10 + int(s[1]-'0'), true
+	}
+	h, ok := digits(s[1:])
+	if !ok {
+		return 0, errTimeSyntax
+	}
+	i, off := 3, h*3600
+	for _, mult := range []int{60, 1} {
+		j := i
+		if j < len(s) && s[j] == ':' {
+			j++
+		}
+		n, ok := digits(s[j:])
+		if !ok {
+			break
+		}
+		off += n * mult
+		i = j + 2
+	}
+	if s[0] == '-' {
+		off = -off
+	}
+	p.zone = time.FixedZone("", off)
+	return i, nil
+}
+
+// zoneOffsets are zone abbreviations read by %Z that are not in the location being parsed in. Abbreviations are
+// ambiguous, so this only has common unambiguous ones; others get a zero offset, as time.Parse does.
+var zoneOffsets = map[string]int{
+	"UTC": 0, "GMT": 0, "UT": 0, "Z": 0,
+	"EST": -5 * 3600, "EDT": -4 * 3600, "CST": -6 * 3600, "CDT": -5 * 3600,
+	"MST": -7 * 3600, "MDT": -6 * 3600, "PST": -8 * 3600, "PDT": -7 * 3600,
+	"AKST": -9 * 3600, "AKDT": -8 * 3600, "HST": -10 * 3600,
+	"WET": 0, "WEST": 3600, "CET": 3600, "CEST": 2 * 3600, "EET": 2 * 3600, "EEST": 3 * 3600,
+	"JST": 9 * 3600, "KST": 9 * 3600, "AEST": 10 * 3600, "AEDT": 11 * 3600, "NZST": 12 * 3600, "NZDT": 13 * 3600,
+}
+
+// time builds the time from the fields read.
+func (p *parsed) time(loc *time.Location) (time.Time, error) {
+	if p.hasEpoch {
+		t := time.Unix(p.epoch, int64(p.nsec))
+		if p.zone != nil {
+			return t.In(p.zone), nil
+		}
+		return t.In(loc), nil
+	}
+	year := p.year
+	switch {
+	case p.hasCentury && p.hasYY:
+		year = p.century*100 + p.yy
+	case p.hasYY && !p.hasYear:
+		year = 1900 + p.yy
+		if p.yy < 69 {
+			year += 100
+		}
+	case p.hasCentury && !p.hasYear:
+		year = p.century * 100
+	}
+	hour := p.hour
+	if p.hour12 {
+		hour %= 12
+		if p.pm {
+			hour += 12
+		}
+	} else if p.hasPM && p.pm && hour < 12 {
+		hour += 12
+	}
+	if p.zone == nil && p.zoneName != "" {
+		p.zone = zoneByName(p.zoneName, year, loc)
+	}
+	if p.zone != nil {
+		loc = p.zone
+	}
+	if p.hasYday {
+		t := time.Date(year, 1, p.yday, hour, p.min, p.sec, p.nsec, loc)
+		if t.Year() != year {
+			return time.Time{}, fmt.Errorf("day of year %d out of range", p.yday)
+		}
+		return t, nil
+	}
+	t := time.Date(year, time.Month(p.month), p.day, hour, p.min, p.sec, p.nsec, loc)
+	if t.Day() != p.day {
+		return time.Time{}, fmt.Errorf("day %d out of range for %s", p.day, time.Month(p.month))
+	}
+	return t, nil
+}
+
+// zoneByName returns the location for a %Z abbreviation: loc if it uses that name around the year, UTC for UTC and
+// GMT, a fixed zone for the names in zoneOffsets, and otherwise a fixed zone with that name and no offset.
+func zoneByName(name string, year int, loc *time.Location) *time.Location {
+	up := strings.ToUpper(name)
+	if up == "UTC" || up == "GMT" || up == "UT" || up == "Z" {
+		return time.UTC
+	}
+	for _, m := range []time.Month{time.January, time.July} {
+		if n, _ := time.Date(year, m, 1, 12, 0, 0, 0, loc).Zone(); strings.EqualFold(n, name) {
+			return loc
+		}
+	}
+	return time.FixedZone(up, zoneOffsets[up])
+}
+
+var weekdayNames = [...]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+var monthNames = [...]string{"January", "February", "March", "April", "May", "June", "July", "August", "September",
+	"October", "November", "December"}
+
+// matchName matches a full or three letter name from names at the start of s, case insensitively, and sets idx to
+// its index.
+func matchName(s string, names []string, idx *int) (int, error) {
+	for i, n := range names {
+		if len(s) >= len(n) && strings.EqualFold(s[:len(n)], n) {
+			*idx = i
+			return len(n), nil
+		}
+	}
+	for i, n := range names {
+		if len(s) >= 3 && strings.EqualFold(s[:3], n[:3]) {
+			*idx = i
+			return 3, nil
+		}
+	}
+	return 0, errTimeSyntax
+}
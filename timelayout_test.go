@@ -0,0 +1,121 @@
+package verbose
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeLayout_RoundTrip(t *testing.T) {
+	nst := time.FixedZone("NST", -(3*3600 + 30*60))
+	times := []time.Time{
+		time.Date(2021, 1, 3, 9, 5, 7, 123456789, nst),
+		time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(2024, 2, 29, 0, 0, 0, 1000000, time.FixedZone("", 5*3600+45*60)),
+		time.Date(2008, 7, 4, 12, 30, 0, 0, time.FixedZone("NST", -(2*3600+30*60))),
+	}
+	formats := []string{
+		"%F %T",
+		"%Y-%m-%dT%H:%M:%S.%N%:z",
+		"%c %z",
+		"%a, %d %b %Y %T %z",
+		"%A %B %e %Y, %l:%M:%S.%6N %p %::z",
+		"%s.%N",
+		"%s%3N",
+		"%D %r %z",
+		"%j %Y %H%M%S %z",
+		"%C%y%m%d %k:%M %P %z",
+		"%Y/%-m/%-d %_H:%M:%S %Z",
+	}
+	for _, f := range formats {
+		l := MustCompileTime(f)
+		for _, want := range times {
+			s := l.Format(want)
+			got, err := l.ParseInLocation(s, want.Location())
+			if err != nil {
+				t.Errorf("%q: %v", f, err)
+				continue
+			}
+			if !got.Equal(want.Truncate(precision(f))) {
+				t.Errorf("%q: formatted %q, parsed back as %v, want %v", f, s, got, want)
+			}
+		}
+	}
+}
+
+// precision returns how much of the time format f keeps.
+func precision(f string) time.Duration {
+	switch {
+	case strings.Contains(f, "%N"):
+		return 1
+	case strings.Contains(f, "%6N"):
+		return time.Microsecond
+	case strings.Contains(f, "%3N"):
+		return time.Millisecond
+	case strings.Contains(f, "%S"), strings.Contains(f, "%T"), strings.Contains(f, "%c"), strings.Contains(f, "%r"):
+		return time.Second
+	}
+	return time.Minute
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		format, value string
+		want          time.Time
+	}{
+		{"%Y-%m-%d %H:%M:%S", "2024-03-05 14:07:09", time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)},
+		{"%d %B %Y", "05 march 2024", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"%a %b %e %T %Y", "TUE MAR  5 14:07:09 2024", time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)},
+		{"%T.%3N", "14:07:09.25", time.Date(0, 1, 1, 14, 7, 9, 250e6, time.UTC)},
+		{"%F %T %Z", "2024-03-05 14:07:09 EST", time.Date(2024, 3, 5, 19, 7, 9, 0, time.UTC)},
+		{"%F %T %Z", "2024-03-05 14:07:09 gmt", time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)},
+		{"%F %T%z", "2024-03-05 14:07:09Z", time.Date(2024, 3, 5, 14, 7, 9, 0, time.UTC)},
+		{"%F %I:%M %p", "2024-03-05 12:30 am", time.Date(2024, 3, 5, 0, 30, 0, 0, time.UTC)},
+		{"%y%m%d", "690101", time.Date(1969, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"%y%m%d", "680101", time.Date(2068, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"%Y %j", "2024 060", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"%s", "1709647629", time.Unix(1709647629, 0).UTC()},
+		{"%%%Y%%", "%2024%", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.format, tt.value)
+		if err != nil {
+			t.Errorf("ParseTime(%q, %q): %v", tt.format, tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q, %q) = %v, want %v", tt.format, tt.value, got, tt.want)
+		}
+	}
+
+	for _, bad := range [][2]string{
+		{"%F", "2024-02-30"},
+		{"%F", "2024-13-01"},
+		{"%F", "2024-03-05 extra"},
+		{"%T", "14:07"},
+		{"%b", "Foo"},
+		{"%Q", "x"},
+		{"%Y%", "2024"},
+	} {
+		if got, err := ParseTime(bad[0], bad[1]); err == nil {
+			t.Errorf("ParseTime(%q, %q) = %v, want an error", bad[0], bad[1], got)
+		}
+	}
+}
+
+func TestParseTime_Local(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	l := MustCompileTime("%F %T %Z")
+	for _, s := range []string{"2024-01-05 10:00:00 EST", "2024-07-05 10:00:00 EDT"} {
+		got, err := l.ParseInLocation(s, ny)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Location() != ny || got.Hour() != 10 {
+			t.Errorf("%s parsed as %v", s, got)
+		}
+	}
+}
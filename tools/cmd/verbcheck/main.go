@@ -0,0 +1,15 @@
+// verbcheck checks code using verbose for exits in libraries, Printj arguments that can't be marshaled and copies of
+// Verb. Format strings given to Printf are checked by go vet itself.
+//
+//	verbcheck ./...
+//	go vet -vettool=$(which verbcheck) ./...
+package main
+
+import (
+	"github.com/rmasci/verbose/tools/verbcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(verbcheck.Analyzer)
+}
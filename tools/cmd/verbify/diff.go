@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// edit is one line of a diff: ' ' kept, '-' removed or '+' added.
+type edit struct {
+	op   byte
+	text string
+}
+
+// diffLines returns the shortest edit script from a to b, using Myers' algorithm.
+func diffLines(a, b []string) []edit {
+	n, m := len(a), len(b)
+	offset := n + m
+	v := make([]int, 2*offset+2)
+	var trace [][]int
+	for d := 0; d <= offset; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset)
+			}
+		}
+	}
+	return nil
+}
+
+func backtrack(trace [][]int, a, b []string, offset int) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{' ', a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				edits = append(edits, edit{'+', b[y]})
+			} else {
+				x--
+				edits = append(edits, edit{'-', a[x]})
+			}
+		}
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// unifiedDiff writes a unified diff of old and new with 3 lines of context. It writes nothing if they are equal.
+func unifiedDiff(w io.Writer, name string, old, new []byte) {
+	a := splitLines(string(old))
+	b := splitLines(string(new))
+	edits := diffLines(a, b)
+	const context = 3
+	changed := false
+	for _, e := range edits {
+		if e.op != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return
+	}
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", name, name)
+	aLine, bLine := 1, 1
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			aLine++
+			bLine++
+			i++
+			continue
+		}
+		// Find the end of the hunk: the first run of more than 2*context unchanged lines.
+		start := max(i-context, 0)
+		end := i
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].op == ' ' {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				end = min(end+context, len(edits))
+				break
+			}
+			end = run
+		}
+		hunkA, hunkB := aLine-(i-start), bLine-(i-start)
+		var countA, countB int
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				countA++
+			}
+			if e.op != '-' {
+				countB++
+			}
+		}
+		fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", hunkA, countA, hunkB, countB)
+		for _, e := range edits[start:end] {
+			text := e.text
+			if !strings.HasSuffix(text, "\n") {
+				text += "\n\\ No newline at end of file\n"
+			}
+			fmt.Fprintf(w, "%c%s", e.op, text)
+		}
+		for _, e := range edits[i:end] {
+			if e.op != '+' {
+				aLine++
+			}
+			if e.op != '-' {
+				bLine++
+			}
+		}
+		i = end
+	}
+}
+
+// splitLines splits s after each newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		edits int
+	}{
+		{"", "", 0},
+		{"a", "a", 0},
+		{"", "a b", 2},
+		{"a b", "", 2},
+		{"a b c", "a x c", 2},
+		// The example from Myers' paper.
+		{"a b c a b b a", "c b a b a c", 5},
+		{"x a b c", "a b c y", 2},
+	}
+	for _, tt := range tests {
+		a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+		var gotA, gotB []string
+		n := 0
+		for _, e := range diffLines(a, b) {
+			if e.op != '+' {
+				gotA = append(gotA, e.text)
+			}
+			if e.op != '-' {
+				gotB = append(gotB, e.text)
+			}
+			if e.op != ' ' {
+				n++
+			}
+		}
+		if strings.Join(gotA, " ") != tt.a || strings.Join(gotB, " ") != tt.b {
+			t.Errorf("diffLines(%q, %q) gives %q and %q", tt.a, tt.b, gotA, gotB)
+		}
+		if n != tt.edits {
+			t.Errorf("diffLines(%q, %q) has %d edits, want %d", tt.a, tt.b, n, tt.edits)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	new := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13"
+	var b strings.Builder
+	unifiedDiff(&b, "f.go", []byte(old), []byte(new))
+	want := `--- f.go
++++ f.go
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+\ No newline at end of file
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	unifiedDiff(&b, "f.go", []byte(old), []byte(old))
+	if b.Len() != 0 {
+		t.Errorf("equal files give %q", b.String())
+	}
+}
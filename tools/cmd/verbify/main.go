@@ -0,0 +1,278 @@
+// verbify rewrites ad-hoc debug prints into verbose calls, so they can stay in the code and only show with -v.
+//
+// It looks for fmt.Print, fmt.Println, fmt.Printf, log.Print, log.Println and log.Printf calls whose first argument
+// is a string literal matching one of the patterns, by default one starting with DEBUG, and turns them into
+// Print, Println or Printf calls on the Verb variable given with --var. Imports are added and removed as needed.
+//
+// By default verbify only shows a diff of what it would change; use -w to write the files.
+//
+//	verbify --var verb ./...
+//	verbify --var util.Verb --import example.com/app/util --strip -w ./cmd/
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// rewrites maps the debug print functions to the Verb method replacing them. log adds a newline if there isn't one, so
+// log.Print and log.Printf get one added to their message. log.Print spaces its arguments like fmt.Print, not
+// Println, so it stays Print.
+var rewrites = map[string]string{
+	"fmt.Print":   "Print",
+	"fmt.Println": "Println",
+	"fmt.Printf":  "Printf",
+	"log.Print":   "Print",
+	"log.Println": "Println",
+	"log.Printf":  "Printf",
+}
+
+type config struct {
+	verb     string
+	importAs string
+	patterns []*regexp.Regexp
+	strip    bool
+}
+
+func main() {
+	var c config
+	var patterns []string
+	var write, list, help bool
+	pflag.StringVar(&c.verb, "var", "verb", "The Verb variable to call, e.g. verb or util.Verb.")
+	pflag.StringVar(&c.importAs, "import", "", "Import path of the package holding --var when it is qualified.")
+	pflag.StringArrayVarP(&patterns, "pattern", "p", []string{`^(?i)debug\b[\s:]*`}, "Regular expression the first string argument must match. Can be repeated.")
+	pflag.BoolVar(&c.strip, "strip", false, "Remove the text matched by the pattern from the message.")
+	pflag.BoolVarP(&write, "write", "w", false, "Write the changes to the files instead of showing a diff.")
+	pflag.BoolVarP(&list, "list", "l", false, "Only list the files that would change.")
+	pflag.BoolVarP(&help, "help", "h", false, "Help")
+	pflag.Parse()
+	if help || pflag.NArg() == 0 {
+		fmt.Printf("Usage: %s [flags] FILE|DIR|DIR/... ...\n", filepath.Base(os.Args[0]))
+		pflag.PrintDefaults()
+		os.Exit(0)
+	}
+	if pkg, _, ok := strings.Cut(c.verb, "."); ok && c.importAs == "" {
+		fmt.Fprintf(os.Stderr, "verbify: --var %s needs --import for package %s\n", c.verb, pkg)
+		os.Exit(2)
+	}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "verbify: --pattern:", err)
+			os.Exit(2)
+		}
+		c.patterns = append(c.patterns, re)
+	}
+
+	failed := false
+	for _, name := range goFiles(pflag.Args()) {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "verbify:", err)
+			failed = true
+			continue
+		}
+		out, n, err := c.rewrite(name, src)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "verbify:", err)
+			failed = true
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		switch {
+		case list:
+			fmt.Println(name)
+		case write:
+			if err := os.WriteFile(name, out, 0644); err != nil {
+				fmt.Fprintln(os.Stderr, "verbify:", err)
+				failed = true
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "%s: %d %s rewritten\n", name, n, plural(n, "call"))
+		default:
+			unifiedDiff(os.Stdout, name, src, out)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func plural(n int, s string) string {
+	if n == 1 {
+		return s
+	}
+	return s + "s"
+}
+
+// goFiles expands the arguments into Go files. DIR/... includes subdirectories, skipping hidden ones, vendor and testdata.
+func goFiles(args []string) []string {
+	var files []string
+	for _, arg := range args {
+		dir, recursive := strings.CutSuffix(arg, "/...")
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != dir && (!recursive || strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasSuffix(path, ".go") {
+				files = append(files, path)
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// rewrite returns src with the matching debug prints turned into Verb calls, and how many were rewritten.
+func (c *config) rewrite(name string, src []byte) ([]byte, int, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+	if err != nil {
+		return nil, 0, err
+	}
+	imports := map[string]string{} // local name to path, for fmt and log
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if path != "fmt" && path != "log" {
+			continue
+		}
+		local := path
+		if imp.Name != nil {
+			local = imp.Name.Name
+		}
+		imports[local] = path
+	}
+
+	n := 0
+	astutil.Apply(file, nil, func(cur *astutil.Cursor) bool {
+		call, ok := cur.Node().(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok || imports[pkg.Name] == "" || pkg.Obj != nil {
+			return true
+		}
+		fn := imports[pkg.Name] + "." + sel.Sel.Name
+		method, ok := rewrites[fn]
+		if !ok {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		msg, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		loc := c.match(msg)
+		if loc == nil {
+			return true
+		}
+		if c.strip {
+			msg = msg[:loc[0]] + msg[loc[1]:]
+		}
+		if (fn == "log.Printf" || fn == "log.Print" && len(call.Args) == 1) && !strings.HasSuffix(msg, "\n") {
+			msg += "\n"
+		}
+		if msg == "" && method != "Printf" && len(call.Args) > 1 {
+			call.Args = call.Args[1:]
+		} else {
+			lit.Value = quote(lit.Value, msg)
+		}
+		if fn == "log.Print" && !endsInNewline(call.Args[len(call.Args)-1]) {
+			// A string operand gets no space before it, so this adds just the newline.
+			call.Args = append(call.Args, &ast.BasicLit{ValuePos: call.Rparen, Kind: token.STRING, Value: `"\n"`})
+		}
+		call.Fun = &ast.SelectorExpr{X: verbExpr(c.verb, sel.Pos()), Sel: ast.NewIdent(method)}
+		n++
+		return true
+	})
+	if n == 0 {
+		return src, 0, nil
+	}
+	for local, path := range imports {
+		if !astutil.UsesImport(file, path) {
+			if local == path {
+				astutil.DeleteImport(fset, file, path)
+			} else {
+				astutil.DeleteNamedImport(fset, file, local, path)
+			}
+		}
+	}
+	if c.importAs != "" {
+		astutil.AddImport(fset, file, c.importAs)
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), n, nil
+}
+
+// match returns the location of the first pattern matching msg, or nil.
+func (c *config) match(msg string) []int {
+	for _, re := range c.patterns {
+		if loc := re.FindStringIndex(msg); loc != nil {
+			return loc
+		}
+	}
+	return nil
+}
+
+// endsInNewline reports whether e is a string literal ending in a newline.
+func endsInNewline(e ast.Expr) bool {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	return err == nil && strings.HasSuffix(s, "\n")
+}
+
+// quote quotes msg like the literal old was quoted, keeping raw strings raw where possible.
+func quote(old, msg string) string {
+	if strings.HasPrefix(old, "`") && !strings.ContainsAny(msg, "`\n") {
+		return "`" + msg + "`"
+	}
+	return strconv.Quote(msg)
+}
+
+// verbExpr parses the --var expression, verb or pkg.Verb.
+func verbExpr(verb string, pos token.Pos) ast.Expr {
+	if pkg, name, ok := strings.Cut(verb, "."); ok {
+		return &ast.SelectorExpr{X: &ast.Ident{NamePos: pos, Name: pkg}, Sel: ast.NewIdent(name)}
+	}
+	return &ast.Ident{NamePos: pos, Name: verb}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		c     config
+		src   string
+		want  string
+		count int
+	}{
+		{
+			name: "fmt",
+			c:    config{verb: "verb"},
+			src: `package p
+
+import "fmt"
+
+func f(x int) {
+	fmt.Println("DEBUG x is", x)
+	fmt.Printf("debug: %d\n", x)
+	fmt.Print("DEBUG ", x)
+	fmt.Println("result", x)
+}
+`,
+			want: `package p
+
+import "fmt"
+
+func f(x int) {
+	verb.Println("DEBUG x is", x)
+	verb.Printf("debug: %d\n", x)
+	verb.Print("DEBUG ", x)
+	fmt.Println("result", x)
+}
+`,
+			count: 3,
+		},
+		{
+			name: "log",
+			c:    config{verb: "verb", strip: true},
+			src: `package p
+
+import "log"
+
+func f(x, y int) {
+	log.Print("DEBUG x=", x)
+	log.Print("DEBUG ", x, y)
+	log.Print("DEBUG ", x, "done\n")
+	log.Print("DEBUG done")
+	log.Printf("DEBUG %d", x)
+	log.Println("DEBUG", x)
+}
+`,
+			want: `package p
+
+func f(x, y int) {
+	verb.Print("x=", x, "\n")
+	verb.Print(x, y, "\n")
+	verb.Print(x, "done\n")
+	verb.Print("done\n")
+	verb.Printf("%d\n", x)
+	verb.Println(x)
+}
+`,
+			count: 6,
+		},
+		{
+			name: "qualified",
+			c:    config{verb: "util.Verb", importAs: "example.com/app/util"},
+			src: `package p
+
+import (
+	"fmt"
+	"os"
+)
+
+func f() {
+	fmt.Println(` + "`DEBUG start`" + `)
+	fmt.Fprintln(os.Stderr, "DEBUG not a print")
+}
+`,
+			want: `package p
+
+import (
+	"example.com/app/util"
+	"fmt"
+	"os"
+)
+
+func f() {
+	util.Verb.Println(` + "`DEBUG start`" + `)
+	fmt.Fprintln(os.Stderr, "DEBUG not a print")
+}
+`,
+			count: 1,
+		},
+		{
+			name: "shadowed",
+			c:    config{verb: "verb"},
+			src: `package p
+
+func f(fmt printer) {
+	fmt.Println("DEBUG kept")
+}
+`,
+			want: `package p
+
+func f(fmt printer) {
+	fmt.Println("DEBUG kept")
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.c.patterns = []*regexp.Regexp{regexp.MustCompile(`^(?i)debug\b[\s:]*`)}
+			got, n, err := tt.c.rewrite("p.go", []byte(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want || n != tt.count {
+				t.Errorf("rewrote %d, want %d, got\n%s\nwant\n%s", n, tt.count, got, tt.want)
+			}
+		})
+	}
+}
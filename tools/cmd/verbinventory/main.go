@@ -0,0 +1,252 @@
+// verbinventory lists every call to a verbose.Verb method in a code base, with its file, line, enclosing function
+// and message, so you can audit what is being traced.
+//
+//	verbinventory ./...
+//	verbinventory -o csv ./... > trace-points.csv
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/tools/go/packages"
+)
+
+const verbosePath = "github.com/rmasci/verbose"
+
+// traceMethods are the Verb methods that write messages. Others are only listed with --all.
+var traceMethods = map[string]bool{
+	"Print": true, "Println": true, "Printf": true, "Printj": true,
+	"PrintlnFunc": true, "PrintfFunc": true,
+	"Fprint": true, "Fprintln": true, "Fprintf": true,
+	"Err": true, "ErrOut": true,
+}
+
+// Site is one call to a Verb method.
+type Site struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Func     string `json:"func"`
+	Receiver string `json:"receiver"`
+	Method   string `json:"method"`
+	// Format is the format string of Printf and Fprintf, the message of Err and ErrOut, and the leading string
+	// constants of the other methods. It is empty when the message is not a constant.
+	Format string `json:"format"`
+}
+
+func main() {
+	var output, tags string
+	var tests, all, help bool
+	pflag.StringVarP(&output, "output", "o", "text", "Output format: text, json or csv.")
+	pflag.StringVar(&tags, "tags", "", "Comma separated build tags.")
+	pflag.BoolVarP(&tests, "tests", "t", false, "Include test files.")
+	pflag.BoolVarP(&all, "all", "a", false, "List calls to every Verb method, not only those that write messages.")
+	pflag.BoolVarP(&help, "help", "h", false, "Help")
+	pflag.Parse()
+	if help {
+		fmt.Printf("Usage: %s [flags] [packages]\n", filepath.Base(os.Args[0]))
+		pflag.PrintDefaults()
+		os.Exit(0)
+	}
+	patterns := pflag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo |
+			packages.NeedImports | packages.NeedDeps,
+		Tests: tests,
+	}
+	if tags != "" {
+		cfg.BuildFlags = []string{"-tags=" + tags}
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "verbinventory:", err)
+		os.Exit(1)
+	}
+	// Report errors but carry on, what did type check is still worth listing.
+	packages.PrintErrors(pkgs)
+
+	wd, _ := os.Getwd()
+	sites := []Site{}
+	seen := map[string]bool{}
+	for _, pkg := range pkgs {
+		for _, s := range inventory(pkg) {
+			if !all && !traceMethods[s.Method] {
+				continue
+			}
+			if rel, err := filepath.Rel(wd, s.File); err == nil && !strings.HasPrefix(rel, "..") {
+				s.File = rel
+			}
+			// Test variants of a package repeat its files.
+			key := s.File + ":" + strconv.Itoa(s.Line) + ":" + s.Method
+			if !seen[key] {
+				seen[key] = true
+				sites = append(sites, s)
+			}
+		}
+	}
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].File != sites[j].File {
+			return sites[i].File < sites[j].File
+		}
+		return sites[i].Line < sites[j].Line
+	})
+
+	switch output {
+	case "json":
+		err = writeJSON(os.Stdout, sites)
+	case "csv":
+		err = writeCSV(os.Stdout, sites)
+	case "text":
+		err = writeText(os.Stdout, sites)
+	default:
+		err = fmt.Errorf("unknown output format %q", output)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "verbinventory:", err)
+		os.Exit(1)
+	}
+}
+
+// inventory finds the Verb method calls in pkg.
+func inventory(pkg *packages.Package) []Site {
+	var sites []Site
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn := "(package level)"
+			if fd, ok := decl.(*ast.FuncDecl); ok {
+				fn = funcName(fd)
+			}
+			ast.Inspect(decl, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || !isVerbMethod(pkg.TypesInfo, sel) {
+					return true
+				}
+				pos := pkg.Fset.Position(call.Pos())
+				sites = append(sites, Site{
+					File:     pos.Filename,
+					Line:     pos.Line,
+					Func:     fn,
+					Receiver: types.ExprString(sel.X),
+					Method:   sel.Sel.Name,
+					Format:   message(pkg.TypesInfo, sel.Sel.Name, call.Args),
+				})
+				return true
+			})
+		}
+	}
+	return sites
+}
+
+// funcName returns Name or (Recv).Name.
+func funcName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	return "(" + types.ExprString(fd.Recv.List[0].Type) + ")." + fd.Name.Name
+}
+
+// isVerbMethod reports whether sel selects a method of verbose.Verb.
+func isVerbMethod(info *types.Info, sel *ast.SelectorExpr) bool {
+	s, ok := info.Selections[sel]
+	if !ok || s.Kind() != types.MethodVal {
+		return false
+	}
+	recv := types.Unalias(s.Recv())
+	if p, ok := recv.(*types.Pointer); ok {
+		recv = types.Unalias(p.Elem())
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == "Verb" && obj.Pkg() != nil && obj.Pkg().Path() == verbosePath
+}
+
+// message returns the constant format string or message of a call, if there is one.
+func message(info *types.Info, method string, args []ast.Expr) string {
+	str := func(e ast.Expr) (string, bool) {
+		tv, ok := info.Types[e]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			return "", false
+		}
+		return constant.StringVal(tv.Value), true
+	}
+	var idx int
+	switch method {
+	case "Printf", "PrintfFunc":
+		idx = 0
+	case "Fprintf", "Err", "ErrOut":
+		idx = 1
+	case "Print", "Println":
+		idx = -1
+	case "Fprint", "Fprintln":
+		args = args[min(1, len(args)):]
+		idx = -1
+	default:
+		return ""
+	}
+	if idx >= 0 {
+		if idx < len(args) {
+			s, _ := str(args[idx])
+			return s
+		}
+		return ""
+	}
+	var parts []string
+	for _, a := range args {
+		s, ok := str(a)
+		if !ok {
+			break
+		}
+		parts = append(parts, s)
+	}
+	if method == "Println" || method == "Fprintln" {
+		return strings.Join(parts, " ")
+	}
+	return strings.Join(parts, "")
+}
+
+func writeText(w io.Writer, sites []Site) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, s := range sites {
+		fmt.Fprintf(tw, "%s:%d\t%s\t%s.%s\t%q\n", s.File, s.Line, s.Func, s.Receiver, s.Method, s.Format)
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, sites []Site) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sites)
+}
+
+func writeCSV(w io.Writer, sites []Site) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"file", "line", "func", "receiver", "method", "format"})
+	for _, s := range sites {
+		cw.Write([]string{s.File, strconv.Itoa(s.Line), s.Func, s.Receiver, s.Method, s.Format})
+	}
+	cw.Flush()
+	return cw.Error()
+}
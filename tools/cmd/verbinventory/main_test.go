@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestInventory(t *testing.T) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo |
+			packages.NeedImports | packages.NeedDeps,
+		// testdata is a module of its own, with a stand-in for verbose.
+		Dir: "testdata",
+	}
+	pkgs, err := packages.Load(cfg, "./app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		t.Fatal("testdata/app doesn't type check")
+	}
+	var got []string
+	for _, s := range inventory(pkgs[0]) {
+		got = append(got, fmt.Sprintf("%s:%d %s %s.%s %q", filepath.Base(s.File), s.Line, s.Func, s.Receiver, s.Method, s.Format))
+	}
+	want := []string{
+		`app.go:15 (package level) verb.Enabled ""`,
+		`app.go:22 (*server).handle s.v.Printf "handling %s\n"`,
+		`app.go:23 (*server).handle s.v.Println "count"`,
+		`app.go:24 (*server).handle s.v.Print "ab"`,
+		`app.go:25 (*server).handle s.v.Fprintf "to stdout %d\n"`,
+		`app.go:26 (*server).handle s.v.Fprintln "fprintln x"`,
+		`app.go:27 (*server).handle s.v.Fprint "fprint"`,
+		`app.go:28 (*server).handle s.v.Printj ""`,
+		`app.go:29 (*server).handle s.v.PrintfFunc "lazy %d\n"`,
+		`app.go:30 (*server).handle s.v.PrintlnFunc ""`,
+		`app.go:31 (*server).handle s.v.Printf ""`,
+		`app.go:35 helper a.Println "through alias"`,
+		`app.go:36 helper a.Err "failed"`,
+		`app.go:37 helper a.ErrOut "fatal"`,
+		`app.go:38 helper a.SetV ""`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+var sites = []Site{
+	{File: "app.go", Line: 22, Func: "(*server).handle", Receiver: "s.v", Method: "Printf", Format: "handling %s\n"},
+	{File: "app.go", Line: 35, Func: "helper", Receiver: "a", Method: "Println", Format: `say "hi", twice`},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b strings.Builder
+	if err := writeCSV(&b, sites); err != nil {
+		t.Fatal(err)
+	}
+	want := "file,line,func,receiver,method,format\n" +
+		"app.go,22,(*server).handle,s.v,Printf,\"handling %s\n\"\n" +
+		"app.go,35,helper,a,Println,\"say \"\"hi\"\", twice\"\n"
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b strings.Builder
+	if err := writeJSON(&b, sites[1:]); err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "file": "app.go",
+    "line": 35,
+    "func": "helper",
+    "receiver": "a",
+    "method": "Println",
+    "format": "say \"hi\", twice"
+  }
+]
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var b strings.Builder
+	if err := writeText(&b, sites); err != nil {
+		t.Fatal(err)
+	}
+	want := "app.go:22  (*server).handle  s.v.Printf  \"handling %s\\n\"\n" +
+		"app.go:35  helper            a.Println   \"say \\\"hi\\\", twice\"\n"
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"errors"
+	"os"
+
+	"github.com/rmasci/verbose"
+)
+
+// Alias is another name for Verb; calls through it are still Verb calls.
+type Alias = verbose.Verb
+
+var verb = verbose.New(os.Stderr)
+
+var on = verb.Enabled()
+
+type server struct {
+	v *verbose.Verb
+}
+
+func (s *server) handle(name string, n int) {
+	s.v.Printf("handling %s\n", name)
+	s.v.Println("count", n, "done")
+	s.v.Print("a", "b", n)
+	s.v.Fprintf(os.Stdout, "to stdout %d\n", n)
+	s.v.Fprintln(os.Stdout, "fprintln", "x")
+	s.v.Fprint(os.Stdout, "fprint")
+	s.v.Printj(n)
+	s.v.PrintfFunc("lazy %d\n", func() []any { return []any{n} })
+	s.v.PrintlnFunc(func() []any { return nil })
+	s.v.Printf(name)
+}
+
+func helper(a *Alias, err error) {
+	a.Println("through alias")
+	a.Err(err, "failed")
+	a.ErrOut(errors.New("x"), "fatal")
+	a.SetV(true)
+}
@@ -0,0 +1,3 @@
+module github.com/rmasci/verbose
+
+go 1.22
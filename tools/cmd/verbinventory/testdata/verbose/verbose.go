@@ -0,0 +1,23 @@
+// Package verbose is a stand-in for github.com/rmasci/verbose with the methods verbinventory lists.
+package verbose
+
+import "io"
+
+type Verb struct {
+	V bool
+}
+
+func New(w io.Writer, a ...any) (v Verb)                     { return v }
+func (v *Verb) Enabled() bool                                { return v.V }
+func (v *Verb) SetV(on bool)                                 {}
+func (v *Verb) Print(a ...any)                               {}
+func (v *Verb) Println(a ...any)                             {}
+func (v *Verb) Printf(format string, a ...any)               {}
+func (v *Verb) PrintlnFunc(f func() []any)                   {}
+func (v *Verb) PrintfFunc(format string, f func() []any)     {}
+func (v *Verb) Printj(data interface{})                      {}
+func (v *Verb) Fprint(w io.Writer, a ...any)                 {}
+func (v *Verb) Fprintln(w io.Writer, a ...any)               {}
+func (v *Verb) Fprintf(w io.Writer, format string, a ...any) {}
+func (v *Verb) Err(err error, str string, e ...bool) bool    { return false }
+func (v *Verb) ErrOut(err error, str string, e ...bool) bool { return false }
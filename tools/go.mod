@@ -0,0 +1,13 @@
+module github.com/rmasci/verbose/tools
+
+go 1.25.0
+
+require (
+	github.com/spf13/pflag v1.0.5
+	golang.org/x/tools v0.44.0
+)
+
+require (
+	golang.org/x/mod v0.35.0 // indirect
+	golang.org/x/sync v0.20.0 // indirect
+)
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/rmasci/verbose"
+)
+
+func main() {
+	verb := verbose.New(os.Stderr)
+	verb.ErrOut(errors.New("x"), "main may exit", true)
+	verb.Printf("%d\n", 1)
+}
@@ -0,0 +1,16 @@
+// Package verbose is a stand-in for github.com/rmasci/verbose with the methods verbcheck looks at.
+package verbose
+
+import "io"
+
+type Verb struct {
+	V bool
+}
+
+func New(w io.Writer, a ...any) (v Verb)                     { return v }
+func (v *Verb) Printf(format string, a ...any)               {}
+func (v *Verb) Fprintf(w io.Writer, format string, a ...any) {}
+func (v *Verb) Println(a ...any)                             {}
+func (v *Verb) Printj(data interface{})                      {}
+func (v *Verb) Err(err error, str string, e ...bool) bool    { return false }
+func (v *Verb) ErrOut(err error, str string, e ...bool) bool { return false }
@@ -0,0 +1,74 @@
+package lib
+
+import (
+	"errors"
+	"os"
+
+	"github.com/rmasci/verbose"
+)
+
+var verb = verbose.New(os.Stderr)
+
+type point struct {
+	X, Y int
+}
+
+type handler struct {
+	Name string
+	Fn   func()
+	skip chan int
+	Skip chan int `json:"-"`
+}
+
+type custom struct {
+	C chan int
+}
+
+func (custom) MarshalJSON() ([]byte, error) { return nil, nil }
+
+// formats are left to vet's printf check, so verbcheck doesn't report them a second time.
+func formats(name string, n int) {
+	verb.Printf("%s is %d\n", name, n)
+	verb.Printf("%d items\n", name)
+	verb.Fprintf(os.Stdout, "%s\n", n)
+}
+
+func exits(err error) {
+	verb.ErrOut(err, "fatal", true) // want `ErrOut with exit set to true in library package lib`
+	verb.Err(err, "fatal", true)    // want `Err with exit set to true in library package lib`
+	verb.ErrOut(err, "not fatal")
+	verb.ErrOut(errors.New("x"), "not fatal", false)
+}
+
+func printj() {
+	verb.Printj(point{})
+	verb.Printj(map[string]int{})
+	verb.Printj(make(chan int))     // want `Printj argument of type chan int can't be marshaled to JSON: channels are not supported`
+	verb.Printj(handler{})          // want `field Fn: funcs are not supported`
+	verb.Printj([]complex128{})     // want `complex numbers are not supported`
+	verb.Printj(map[point]string{}) // want `map key type lib.point is not supported`
+	verb.Printj(custom{})
+	var anything any = make(chan int)
+	verb.Printj(anything)
+}
+
+func use(v *verbose.Verb) {}
+
+func byValue(v verbose.Verb) {} // want `parameter passes Verb by value`
+
+func copies() verbose.Verb {
+	v := verbose.New(os.Stdout)
+	p := &v
+	c := v     // want `assignment copies Verb`
+	c = *p     // want `assignment copies Verb`
+	byValue(v) // want `call passes Verb by value`
+	use(&c)
+	var d = verb // want `variable declaration copies Verb`
+	use(&d)
+	for _, x := range []verbose.Verb{} { // want `range variable copies Verb`
+		use(&x)
+	}
+	_ = []verbose.Verb{verbose.New(os.Stdout)}
+	_ = []verbose.Verb{v} // want `composite literal copies Verb`
+	return v              // want `return copies Verb`
+}
@@ -0,0 +1,242 @@
+// Package verbcheck is a go/analysis analyzer for code using verbose.
+//
+// It checks that:
+//
+//   - ErrOut and Err are not called with exit set to true outside package main, where exiting is the caller's call.
+//   - Printj is not given a value encoding/json can't marshal, such as a channel, func or complex number.
+//   - Verb is not copied by value. A copy doesn't see changes made through HandleSignals, AdminHandler or
+//     ListenControl, and they don't see changes made to the copy.
+//
+// Printf and Fprintf format strings are not checked here: go vet's printf check sees that they pass their format to
+// fmt.Appendf and checks their calls itself. Nothing checks the format of PrintfFunc, whose arguments come from a
+// func, and under the noverbose build tag Printf and Fprintf are empty, so vet doesn't check them either.
+//
+// Run it with cmd/verbcheck, or with go vet -vettool.
+package verbcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const verbosePath = "github.com/rmasci/verbose"
+
+// Analyzer reports misuse of verbose.Verb.
+var Analyzer = &analysis.Analyzer{
+	Name:     "verbcheck",
+	Doc:      "check verbose.Verb exits in libraries, Printj arguments and copies of Verb",
+	URL:      "https://pkg.go.dev/github.com/rmasci/verbose/tools/verbcheck",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inVerbose := pass.Pkg.Path() == verbosePath
+	nodes := []ast.Node{
+		(*ast.CallExpr)(nil),
+		(*ast.FuncType)(nil),
+		(*ast.FuncDecl)(nil),
+		(*ast.AssignStmt)(nil),
+		(*ast.ValueSpec)(nil),
+		(*ast.ReturnStmt)(nil),
+		(*ast.RangeStmt)(nil),
+		(*ast.CompositeLit)(nil),
+	}
+	insp.Preorder(nodes, func(n ast.Node) {
+		if call, ok := n.(*ast.CallExpr); ok {
+			checkCall(pass, call)
+		}
+		// The verbose package copies Verb on purpose, in New and to save settings.
+		if !inVerbose {
+			checkCopy(pass, n)
+		}
+	})
+	return nil, nil
+}
+
+// isVerb reports whether t is verbose.Verb.
+func isVerb(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Name() == "Verb" && obj.Pkg() != nil && obj.Pkg().Path() == verbosePath
+}
+
+// verbMethod returns the name of the Verb method called, or "".
+func verbMethod(pass *analysis.Pass, call *ast.CallExpr) string {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	s, ok := pass.TypesInfo.Selections[sel]
+	if !ok || s.Kind() != types.MethodVal {
+		return ""
+	}
+	recv := s.Recv()
+	if p, ok := recv.(*types.Pointer); ok {
+		recv = p.Elem()
+	}
+	if !isVerb(recv) {
+		return ""
+	}
+	return sel.Sel.Name
+}
+
+func checkCall(pass *analysis.Pass, call *ast.CallExpr) {
+	switch method := verbMethod(pass, call); method {
+	case "Err", "ErrOut":
+		if len(call.Args) > 2 && pass.Pkg.Name() != "main" && isTrue(pass, call.Args[2]) {
+			pass.ReportRangef(call, "%s with exit set to true in library package %s: return the error and let main decide to exit", method, pass.Pkg.Name())
+		}
+	case "Printj":
+		if len(call.Args) == 1 {
+			if t := pass.TypesInfo.TypeOf(call.Args[0]); t != nil {
+				if why := unmarshalable(t, map[types.Type]bool{}); why != "" {
+					pass.ReportRangef(call.Args[0], "Printj argument of type %s can't be marshaled to JSON: %s", t, why)
+				}
+			}
+		}
+	}
+}
+
+func isTrue(pass *analysis.Pass, e ast.Expr) bool {
+	tv := pass.TypesInfo.Types[e]
+	return tv.Value != nil && tv.Value.Kind() == constant.Bool && constant.BoolVal(tv.Value)
+}
+
+// unmarshalable returns why encoding/json can't marshal values of type t, or "" if it can, as far as the static type
+// tells. Interfaces and types with their own MarshalJSON or MarshalText are assumed to be fine.
+func unmarshalable(t types.Type, seen map[types.Type]bool) string {
+	if seen[t] {
+		return ""
+	}
+	seen[t] = true
+	if hasMethod(t, "MarshalJSON") || hasMethod(t, "MarshalText") {
+		return ""
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Chan:
+		return "channels are not supported"
+	case *types.Signature:
+		return "funcs are not supported"
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsComplex != 0:
+			return "complex numbers are not supported"
+		case u.Kind() == types.UnsafePointer:
+			return "unsafe.Pointer is not supported"
+		}
+	case *types.Pointer:
+		return unmarshalable(u.Elem(), seen)
+	case *types.Slice:
+		return unmarshalable(u.Elem(), seen)
+	case *types.Array:
+		return unmarshalable(u.Elem(), seen)
+	case *types.Map:
+		key := u.Key()
+		if b, ok := key.Underlying().(*types.Basic); !(ok && b.Info()&(types.IsString|types.IsInteger) != 0) && !hasMethod(key, "MarshalText") {
+			return fmt.Sprintf("map key type %s is not supported", key)
+		}
+		return unmarshalable(u.Elem(), seen)
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			f := u.Field(i)
+			if !f.Exported() && !f.Embedded() {
+				continue
+			}
+			if tag := reflect.StructTag(u.Tag(i)).Get("json"); tag == "-" {
+				continue
+			}
+			if why := unmarshalable(f.Type(), seen); why != "" {
+				return "field " + f.Name() + ": " + why
+			}
+		}
+	}
+	return ""
+}
+
+func hasMethod(t types.Type, name string) bool {
+	for _, typ := range []types.Type{t, types.NewPointer(t)} {
+		if obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, name); obj != nil {
+			if _, ok := obj.(*types.Func); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkCopy reports places where an existing Verb value is copied.
+func checkCopy(pass *analysis.Pass, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.FuncDecl:
+		if n.Recv != nil {
+			for _, f := range n.Recv.List {
+				if isVerb(pass.TypesInfo.TypeOf(f.Type)) {
+					pass.ReportRangef(f, "receiver passes Verb by value, use *verbose.Verb")
+				}
+			}
+		}
+	case *ast.FuncType:
+		if n.Params != nil {
+			for _, f := range n.Params.List {
+				if isVerb(pass.TypesInfo.TypeOf(f.Type)) {
+					pass.ReportRangef(f, "parameter passes Verb by value, use *verbose.Verb")
+				}
+			}
+		}
+	case *ast.CallExpr:
+		if tv, ok := pass.TypesInfo.Types[n.Fun]; ok && tv.IsType() {
+			return // conversion
+		}
+		for _, arg := range n.Args {
+			reportCopy(pass, arg, "call passes Verb by value, pass a pointer")
+		}
+	case *ast.AssignStmt:
+		for _, rhs := range n.Rhs {
+			reportCopy(pass, rhs, "assignment copies Verb, use a pointer")
+		}
+	case *ast.ValueSpec:
+		for _, v := range n.Values {
+			reportCopy(pass, v, "variable declaration copies Verb, use a pointer")
+		}
+	case *ast.ReturnStmt:
+		for _, r := range n.Results {
+			reportCopy(pass, r, "return copies Verb, return a pointer")
+		}
+	case *ast.RangeStmt:
+		if n.Value != nil && isVerb(pass.TypesInfo.TypeOf(n.Value)) {
+			pass.ReportRangef(n.Value, "range variable copies Verb, range over the index instead")
+		}
+	case *ast.CompositeLit:
+		for _, elt := range n.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			reportCopy(pass, elt, "composite literal copies Verb, use a pointer")
+		}
+	}
+}
+
+// reportCopy reports e if it is an existing Verb value. Values that are made on the spot, such as the result of
+// verbose.New or a composite literal, are not copies.
+func reportCopy(pass *analysis.Pass, e ast.Expr, msg string) {
+	if !isVerb(pass.TypesInfo.TypeOf(e)) {
+		return
+	}
+	switch ast.Unparen(e).(type) {
+	case *ast.CallExpr, *ast.CompositeLit:
+		return
+	}
+	pass.ReportRangef(e, "%s", msg)
+}
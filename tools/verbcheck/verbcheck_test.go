@@ -0,0 +1,12 @@
+package verbcheck_test
+
+import (
+	"testing"
+
+	"github.com/rmasci/verbose/tools/verbcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), verbcheck.Analyzer, "lib", "app")
+}
@@ -25,13 +25,23 @@ package verbose
 import (
 	"fmt"
 	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Verb struct {
-	// V when set to true enables the verbose printing.
+	// V when set to true enables the verbose printing. Set it before the Verb is shared, e.g. with flag.BoolVar. Once
+	// SetV, HandleSignals, AdminHandler, ListenControl or ForTest has turned verbose mode on or off, that setting is
+	// used instead and V isn't read again.
 	V bool
 	// set the date format using standard Go Formatting 2006/01/02 15:04:05
 	Dformat string
+	// Strftime, when set, is used instead of Dformat to print the date, as a date(1) format. See the Strftime func.
+	// New sets it when its format uses directives TimeFormatStr can't translate to a Go layout, with %N and %P made
+	// .%3N and %p so they print as TimeFormatStr's .000 and PM do.
+	Strftime string
 	// Set the delimiter between date, line number and print string.
 	Delimeter string
 	// If set to false, date will not be printed
@@ -39,30 +49,135 @@ type Verb struct {
 	// If set to false, line number will not be printed
 	PrintLine bool
 	// Set where to write the print statements. By default it's stderr, but you can change it to stdout, or to a file.
-	Out io.Writer `default0:os.Stderr`
+	Out io.Writer `default0:"os.Stderr"`
 	// Quit is a verbose channel
 	Quit chan bool
+	// Redactor masks passwords, tokens and other secrets in everything written. nil disables rule based redaction,
+	// Secret values and fields tagged `verbose:"redact"` are always masked.
+	Redactor *Redactor
+	// Anonymizer replaces IP addresses, email addresses and host names with stable pseudonyms. nil disables it.
+	Anonymizer *Anonymizer
+	// Hits counts how often each Print, Fprint, Printj, Err and ErrOut call runs, whether or not verbose mode is on.
+	// nil disables counting.
+	Hits *HitCounter
+	// Clock gives the time printed with each message. nil uses the system clock.
+	Clock Clock
+	// Location is the time zone dates are printed in, e.g. time.UTC, so logs from servers in different zones line
+	// up. nil prints them in the Clock's zone, normally the host's.
+	Location *time.Location
+
+	hub   *hub
+	async *async
+	// outMu serializes writes to Out, which are made without mu so Out may print itself, e.g. be another Verb's
+	// Writer. New sets it, so copies of a Verb share it; writeLock covers a Verb made without New.
+	outMu *sync.Mutex
+	// on is verbose mode as set by SetV: vUnset until then, else vOff or vOn. Only accessed atomically.
+	on int32
 }
 
+// The values of Verb.on.
+const (
+	vUnset int32 = iota
+	vOff
+	vOn
+)
+
 // Returns a type Verb and sets some defaults.
 // If nothing passed verbose.New(), no date is used
 // if verbose.New("default") use a default date string.
 // Date string can be customized by either setting Dformat using go date format string or by passing a linux date compatible string to verbose.New.
+// A preset name like "rfc3339" or "epochms" can be passed instead, see PresetFormat, and a *time.Location to print
+// the dates in that zone:
+//
+//	verb := verbose.New(os.Stderr, "rfc3339", time.UTC)
 func New(w io.Writer, a ...any) (v Verb) {
+	a = v.takeLocation(a)
 	if len(a) <= 0 {
 		v.Dformat = "2006-01-02 15:04:05 "
 	} else if a[0] == "default" || a[0] == "" {
 		v.Dformat = "2006-01-02 15:04:05 "
 		v.PrintDate = true
-		return v
 	} else {
 		str := fmt.Sprintln(a...)
 		v.PrintDate = true
-		v.Dformat = TimeFormatStr(str)
+		v.setStrftime(str)
 	}
 
 	v.Delimeter = " "
 	v.Out = w
 	v.Quit = make(chan bool)
+	v.outMu = new(sync.Mutex)
 	return v
 }
+
+// takeLocation sets Location from the first *time.Location in a and returns the other arguments.
+func (v *Verb) takeLocation(a []any) []any {
+	rest := a[:0:0]
+	for _, x := range a {
+		if loc, ok := x.(*time.Location); ok {
+			if v.Location == nil {
+				v.Location = loc
+			}
+			continue
+		}
+		rest = append(rest, x)
+	}
+	return rest
+}
+
+// setStrftime sets Dformat to the Go layout for the date(1) format, and Strftime to the format if the layout doesn't
+// print the same thing. A preset name sets the preset's format.
+func (v *Verb) setStrftime(format string) {
+	if layout, strftime, ok := PresetFormat(format); ok {
+		v.Dformat, v.Strftime = layout, strftime
+		return
+	}
+	v.Dformat = TimeFormatStr(format)
+	v.Strftime = ""
+	if format = strings.TrimSpace(format); !translatable(format) {
+		v.Strftime = legacyStrftime(format)
+	}
+}
+
+func MyTest() string {
+	return "Successfull"
+}
+
+// Enabled reports whether verbose mode is on: the last SetV, or V if SetV hasn't been called. Use it instead of
+// reading V when verbose mode may be changed from another goroutine, for example by HandleSignals. It doesn't lock.
+func (v *Verb) Enabled() bool {
+	switch atomic.LoadInt32(&v.on) {
+	case vOn:
+		return true
+	case vOff:
+		return false
+	}
+	return v.V
+}
+
+// SetV turns verbose mode on or off. It is safe to call while other goroutines are printing.
+func (v *Verb) SetV(on bool) {
+	state := vOff
+	if on {
+		state = vOn
+	}
+	atomic.StoreInt32(&v.on, state)
+}
+
+// switchV sets V and logs the change and its reason, even when verbose mode is being turned off.
+func (v *Verb) switchV(on bool, reason string) {
+	mu.Lock()
+	defer mu.Unlock()
+	state := "off"
+	if on {
+		state = "on"
+	}
+	v.logChange("%s, verbose mode %s", reason, state)
+	v.SetV(on)
+}
+
+// logChange writes a message about a change to the Verb's settings whether or not verbose mode is on. mu must be held;
+// it is released while the message is written.
+func (v *Verb) logChange(format string, a ...any) {
+	v.output(v.Out, 1, append(fmt.Appendf([]byte("verbose: "), format, a...), '\n'))
+}
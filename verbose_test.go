@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -38,6 +39,30 @@ func Test_verboseNew(t *testing.T) {
 	}
 }
 
+func Test_verboseNewDirectives(t *testing.T) {
+	needsVerbose(t)
+	when := time.Date(2024, 3, 5, 14, 7, 9, 123456789, time.Local)
+	// A directive TimeFormatStr can't translate must not change what the others print.
+	for _, tt := range []struct{ format, want string }{
+		{"%T%N", "14:07:09.123"},
+		{"%T%N %k", "14:07:09.123 14"},
+		{"%F %T.%N", "2024-03-05 14:07:09.123456789"},
+		{"%F %T.%N %k", "2024-03-05 14:07:09.123456789 14"},
+		{"%I %P", "02 PM"},
+		{"%I %P %k", "02 PM 14"},
+		{"%-I%P %%N", "2PM %N"},
+	} {
+		var buf strings.Builder
+		verb := New(&buf, tt.format)
+		verb.V = true
+		verb.Clock = NewStepClock(when, 0)
+		verb.Println("x")
+		if got := buf.String(); got != tt.want+" x\n" {
+			t.Errorf("New(%q) printed %q, want %q", tt.format, got, tt.want+" x\n")
+		}
+	}
+}
+
 func Test_timeFormatStr(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -72,6 +97,7 @@ func Test_timeFormatStr(t *testing.T) {
 }
 
 func Test_verbPrint(t *testing.T) {
+	needsVerbose(t)
 	var tn string
 	tests := []struct {
 		name      string
@@ -106,6 +132,9 @@ func Test_verbPrint(t *testing.T) {
 		} else {
 			verb.Delimeter = test.delim
 		}
+		if test.delim == "" {
+			test.delim = " " // an empty Delimeter prints as a space
+		}
 		verb.PrintLine = test.printline
 		verb.PrintDate = test.printdate
 		switch test.action {
@@ -125,9 +154,9 @@ func Test_verbPrint(t *testing.T) {
 			verb.Fprintf(w, "%s", test.text)
 			expected = fmt.Sprintf("%s%s%s", tn, test.delim, test.text)
 		case "number":
+			_, _, line, _ := runtime.Caller(0)
 			verb.Printf("%s", test.text)
-			// 116 must equal the line number above
-			expected = fmt.Sprintf("%s%sverbose_test.go:118%s%s", tn, test.delim, test.delim, test.text)
+			expected = fmt.Sprintf("%s%sverbose_test.go:%d%s%s", tn, test.delim, line+1, test.delim, test.text)
 		case "nodate":
 			verb.Printf("%s", test.text)
 			expected = fmt.Sprintf("%s", test.text)
@@ -0,0 +1,166 @@
+// Package verbosetest captures what a verbose.Verb prints so tests can check it, instead of pointing Out at an
+// os.Pipe and reading it back.
+//
+//	func TestQuery(t *testing.T) {
+//		verb, rec := verbosetest.New(t)
+//		runQuery(verb)
+//		verbosetest.AssertLogged(t, rec, "Query:")
+//		verbosetest.AssertGolden(t, rec, "testdata/query.golden")
+//	}
+//
+// Golden files are rewritten by running the tests with -verbosetest.update.
+package verbosetest
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rmasci/verbose"
+)
+
+var update = flag.Bool("verbosetest.update", false, "rewrite verbosetest golden files")
+
+// Recorder keeps every message a Verb writes, in order.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []verbose.Entry
+	remove  func()
+}
+
+// Record records v's messages until the test ends. Only messages that are written are recorded, so V must be on.
+func Record(t testing.TB, v *verbose.Verb) *Recorder {
+	r := &Recorder{}
+	r.remove = v.AddHook(r.add)
+	t.Cleanup(r.Stop)
+	return r
+}
+
+// Epoch is where the Clock of a Verb from New starts.
+var Epoch = time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// New returns a Verb for a test: it is on, prints the date, writes nothing and is recorded. Its Clock starts at Epoch
+// and moves a millisecond per message, so its timestamps are the same every run.
+func New(t testing.TB) (*verbose.Verb, *Recorder) {
+	v := verbose.New(io.Discard, "default")
+	v.V = true
+	v.Clock = NewClock(Epoch, time.Millisecond)
+	return &v, Record(t, &v)
+}
+
+func (r *Recorder) add(e verbose.Entry) {
+	r.mu.Lock()
+	r.entries = append(r.entries, e)
+	r.mu.Unlock()
+}
+
+// Entries returns the recorded messages.
+func (r *Recorder) Entries() []verbose.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]verbose.Entry(nil), r.entries...)
+}
+
+// Messages returns the recorded messages without their date and line prefix or trailing newline.
+func (r *Recorder) Messages() []string {
+	var msgs []string
+	for _, e := range r.Entries() {
+		msgs = append(msgs, strings.TrimSuffix(e.Msg, "\n"))
+	}
+	return msgs
+}
+
+// Text returns the recorded messages as they were written.
+func (r *Recorder) Text() string {
+	var b strings.Builder
+	for _, e := range r.Entries() {
+		b.WriteString(e.Text)
+	}
+	return b.String()
+}
+
+// Reset forgets what has been recorded so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.entries = nil
+	r.mu.Unlock()
+}
+
+// Stop stops recording. Record calls it when the test ends.
+func (r *Recorder) Stop() {
+	r.remove()
+}
+
+// AssertLogged fails the test unless a recorded message contains substr. It reports whether one did.
+func AssertLogged(t testing.TB, r *Recorder, substr string) bool {
+	t.Helper()
+	for _, m := range r.Messages() {
+		if strings.Contains(m, substr) {
+			return true
+		}
+	}
+	t.Errorf("no verbose message contains %q, got:\n%s", substr, r.Text())
+	return false
+}
+
+// AssertNotLogged fails the test if a recorded message contains substr. It reports whether none did.
+func AssertNotLogged(t testing.TB, r *Recorder, substr string) bool {
+	t.Helper()
+	for _, m := range r.Messages() {
+		if strings.Contains(m, substr) {
+			t.Errorf("verbose message %q contains %q", m, substr)
+			return false
+		}
+	}
+	return true
+}
+
+// AssertGolden compares the recorded text, with timestamps normalized, to the golden file. With -verbosetest.update
+// it writes the file instead.
+func AssertGolden(t testing.TB, r *Recorder, file string) {
+	t.Helper()
+	got := []byte(Normalize(r.Text()))
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("%v (run with -verbosetest.update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("verbose output differs from %s\ngot:\n%s\nwant:\n%s", file, got, want)
+	}
+}
+
+var (
+	dateRE = regexp.MustCompile(`\b\d{4}[-/]\d{2}[-/]\d{2}\b`)
+	timeRE = regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}(\.\d+)?\b`)
+)
+
+// Normalize replaces dates like 2006-01-02 or 2006/01/02 with <date> and times like 15:04:05 or 15:04:05.000 with
+// <time>, so output can be compared across runs.
+func Normalize(text string) string {
+	text = dateRE.ReplaceAllString(text, "<date>")
+	return timeRE.ReplaceAllString(text, "<time>")
+}
+
+// Clock is the fake clock New gives its Verb. Now steps it on and Sleep doesn't wait.
+type Clock = verbose.StepClock
+
+// NewClock returns a Clock starting at start that moves step per call to Now.
+func NewClock(start time.Time, step time.Duration) *Clock {
+	return verbose.NewStepClock(start, step)
+}
@@ -0,0 +1,89 @@
+//go:build !noverbose
+
+package verbosetest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	verb, rec := New(t)
+	verb.Println("Query:", "select 1")
+	verb.Printf("rows: %d\n", 1)
+	verb.V = false
+	verb.Println("not printed")
+	verb.V = true
+	verb.Err(errors.New("timeout"), "query failed")
+
+	AssertLogged(t, rec, "Query:")
+	AssertNotLogged(t, rec, "not printed")
+	if got := rec.Messages(); len(got) != 3 || got[1] != "rows: 1" {
+		t.Errorf("Messages() = %q", got)
+	}
+	if e := rec.Entries()[0]; e.File != "verbosetest_test.go" || e.Line == 0 || !e.Time.Equal(Epoch) {
+		t.Errorf("first entry %+v", e)
+	}
+	verb.PrintLine = false
+	rec.Reset()
+	verb.Println("after reset")
+	AssertGolden(t, rec, "testdata/recorder.golden")
+}
+
+func TestAssertLogged(t *testing.T) {
+	verb, rec := New(t)
+	verb.Println("something else")
+	var ft fakeT
+	if AssertLogged(&ft, rec, "Query:") || !ft.failed {
+		t.Error("AssertLogged passed without the message")
+	}
+	ft = fakeT{}
+	if AssertNotLogged(&ft, rec, "else") || !ft.failed {
+		t.Error("AssertNotLogged passed with the message")
+	}
+}
+
+// fakeT is a testing.TB that records failures instead of failing.
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper()               {}
+func (f *fakeT) Errorf(string, ...any) { f.failed = true }
+func (f *fakeT) Fatalf(string, ...any) { f.failed = true }
+func (f *fakeT) Cleanup(func())        {}
+
+func TestRecord_Stop(t *testing.T) {
+	verb, rec := New(t)
+	rec.Stop()
+	verb.Println("after stop")
+	if len(rec.Entries()) != 0 {
+		t.Errorf("recorded after Stop: %q", rec.Messages())
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	in := "2024-05-06 07:08:09 started\n2024/05/06 07:08:09.123 done at 12:00:00\nversion 1.22.0"
+	want := "<date> <time> started\n<date> <time> done at <time>\nversion 1.22.0"
+	if got := Normalize(in); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestClock(t *testing.T) {
+	c := NewClock(Epoch, time.Second)
+	if !c.Now().Equal(Epoch) || !c.Now().Equal(Epoch.Add(time.Second)) {
+		t.Error("Now doesn't step")
+	}
+	c.Advance(time.Hour)
+	if got := c.Now(); !got.Equal(Epoch.Add(time.Hour + 2*time.Second)) {
+		t.Errorf("after Advance Now() = %v", got)
+	}
+	c.Set(Epoch)
+	if !strings.HasPrefix(c.Now().String(), "2000-01-02 03:04:05") {
+		t.Error("Set didn't set the time")
+	}
+}
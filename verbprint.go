@@ -1,104 +1,99 @@
+//go:build !noverbose
+
 package verbose
 
 import (
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
-	"runtime"
-	"time"
 )
 
+// verboseBuild is false when built with the noverbose tag, see noverbose.go.
+const verboseBuild = true
+
 // Just like fmt.Print -- only prints when verbose.V is true. Only prints the date and line number if PrintDate and PrintLine are true
 func (v *Verb) Print(a ...any) {
-	if v.V {
-		if v.Delimeter == "" {
-			v.Delimeter = " "
-		}
-		if v.PrintDate == true {
-			tn := time.Now().Format(v.Dformat)
-			fmt.Fprintf(v.Out, "%s%s", tn, v.Delimeter)
-		}
-		if v.PrintLine {
-			_, file, line, ok := runtime.Caller(1)
-			if !ok {
-				file = "???"
-				line = 0
-			} else {
-				file = filepath.Base(file)
-			}
-			fmt.Fprintf(v.Out, "%s:%d%s", file, line, v.Delimeter)
-		}
-		fmt.Fprint(v.Out, a...)
+	v.hit()
+	if v.Enabled() {
+		b := getBuf()
+		*b = fmt.Append(*b, a...)
+		v.print(nil, *b)
+		putBuf(b)
 	}
 }
 
 // Just like fmt.Println -- only prints when verbose.V is true,  Only prints the date and line number if PrintDate and PrintLine are true
 func (v *Verb) Println(a ...any) {
-	if v.V {
-		if v.Out == nil {
-			v.Out = os.Stdout
-		}
-		if v.Delimeter == "" {
-			v.Delimeter = " "
-		}
-		if v.PrintDate == true {
-			tn := time.Now().Format(v.Dformat)
-			fmt.Fprintf(v.Out, "%s%s", tn, v.Delimeter)
-		}
-		if v.PrintLine {
-			_, file, line, ok := runtime.Caller(1)
-			if !ok {
-				file = "???"
-				line = 0
-			} else {
-				file = filepath.Base(file)
-			}
-			fmt.Fprintf(v.Out, "%s:%d%s", file, line, v.Delimeter)
-		}
-		fmt.Fprintln(v.Out, a...)
+	v.hit()
+	if v.Enabled() {
+		b := getBuf()
+		*b = fmt.Appendln(*b, a...)
+		v.print(nil, *b)
+		putBuf(b)
 	}
 }
 
 // Just like fmt.Printf, but only prints if verb.V is true  Only prints the date and line number if PrintDate and PrintLine are true
 func (v *Verb) Printf(format string, a ...any) {
-	if v.V {
-		if v.Out == nil {
-			v.Out = os.Stdout
-		}
-		if v.Delimeter == "" {
-			v.Delimeter = " "
-		}
-		if v.PrintDate == true {
-			tn := time.Now().Format(v.Dformat)
-			fmt.Fprintf(v.Out, "%s%s", tn, v.Delimeter)
-		}
+	v.hit()
+	if v.Enabled() {
+		b := getBuf()
+		*b = fmt.Appendf(*b, format, a...)
+		v.print(nil, *b)
+		putBuf(b)
+	}
+}
 
-		if v.PrintLine {
-			_, file, line, ok := runtime.Caller(1)
-			if !ok {
-				file = "???"
-				line = 0
-			} else {
-				file = filepath.Base(file)
-			}
-			fmt.Fprintf(v.Out, "%s:%d%s", file, line, v.Delimeter)
-		}
-		fmt.Fprintf(v.Out, format, a...)
+// PrintlnFunc is Println for arguments that are expensive to compute. f is only called when verbose mode is on.
+//
+//	verb.PrintlnFunc(func() []any { return []any{"state:", expensiveDump()} })
+func (v *Verb) PrintlnFunc(f func() []any) {
+	v.hit()
+	if v.Enabled() {
+		b := getBuf()
+		*b = fmt.Appendln(*b, f()...)
+		v.print(nil, *b)
+		putBuf(b)
+	}
+}
+
+// PrintfFunc is Printf for arguments that are expensive to compute. f is only called when verbose mode is on.
+func (v *Verb) PrintfFunc(format string, f func() []any) {
+	v.hit()
+	if v.Enabled() {
+		b := getBuf()
+		*b = fmt.Appendf(*b, format, f()...)
+		v.print(nil, *b)
+		putBuf(b)
 	}
 }
 
 // Prints a interface (struct) in indented JSON. Only prints if verb.V is true  Line numbers are not printed.
+// Struct fields tagged `verbose:"redact"` are masked before marshaling.
 func (v *Verb) Printj(data interface{}) {
+	v.hit()
+	on := v.Enabled()
+	var jsonData []byte
+	var err error
+	if on {
+		// Marshal before taking the lock: a Lazy or a MarshalJSON method may print with a Verb itself.
+		if l, ok := data.(Lazy); ok {
+			data = l()
+		}
+		jsonData, err = json.MarshalIndent(redactTagged(data, v.mask()), "", "  ")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	now := v.now()
 	if v.PrintDate == true {
-		fmt.Println(time.Now().Format(v.Dformat))
+		fmt.Println(string(v.appendDate(nil, now)))
 	}
-	if v.V {
-		jsonData, err := json.MarshalIndent(data, "", "  ")
+	if on {
 		if err != nil {
-			fmt.Fprintf(v.Out, "Error marshaling data: %v\n", err)
+			v.write(v.Out, fmt.Appendf(nil, "Error marshaling data: %v\n", err))
 			return
 		}
-		fmt.Fprintln(v.Out, string(jsonData))
+		msg := v.filter(string(jsonData)) + "\n"
+		v.write(v.Out, []byte(msg))
+		v.publish(Entry{Time: now, Msg: msg, Text: msg})
 	}
 }
@@ -2,14 +2,19 @@ package verbose
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"testing"
+	"time"
 )
 
 func TestVerb_Printf(t *testing.T) {
+	needsVerbose(t)
 	// Create a new Verb instance
-	v := New()
+	v := New(nil)
+	v.V = true
 
 	// Set up test cases
 	tests := []struct {
@@ -80,6 +85,11 @@ func TestNew(t *testing.T) {
 	for _, test := range tests {
 		// Call the New function
 		got := New(test.w, test.a...)
+		if got.Quit == nil {
+			t.Errorf("New(%v, %v) didn't make Quit", test.w, test.a)
+		}
+		got.Quit = nil  // a new channel every time
+		got.outMu = nil // and a new lock
 
 		// Check if the output matches the expected value
 		if got != test.expected {
@@ -89,8 +99,10 @@ func TestNew(t *testing.T) {
 }
 
 func TestVerb_Print(t *testing.T) {
+	needsVerbose(t)
 	// Create a new Verb instance
-	v := New()
+	v := New(nil)
+	v.Clock = NewStepClock(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), 0)
 
 	// Set up test cases
 	tests := []struct {
@@ -109,7 +121,7 @@ func TestVerb_Print(t *testing.T) {
 			PrintLine:      true,
 			Dformat:        "2006-01-02 15:04:05",
 			a:              []interface{}{"Hello", "World"},
-			expectedOutput: "2006-01-02 15:04:05 verbprint_test.go:25 Hello World",
+			expectedOutput: "2006-01-02 15:04:05 verbprint_test.go:%d HelloWorld",
 		},
 		{
 			V:              false,
@@ -140,17 +152,22 @@ func TestVerb_Print(t *testing.T) {
 		v.Dformat = test.Dformat
 
 		// Call the Print method
+		_, _, line, _ := runtime.Caller(0)
 		v.Print(test.a...)
 
 		// Check if the output matches the expected value
+		if test.expectedOutput != "" {
+			test.expectedOutput = fmt.Sprintf(test.expectedOutput, line+1)
+		}
 		if got := buf.String(); got != test.expectedOutput {
 			t.Errorf("Print(%v) = %q, want %q", test.a, got, test.expectedOutput)
 		}
 	}
 }
 func TestVerb_Printj(t *testing.T) {
+	needsVerbose(t)
 	// Create a new Verb instance
-	v := New()
+	v := New(nil)
 
 	// Set up test cases
 	tests := []struct {
@@ -1,3 +1,5 @@
+//go:build !noverbose
+
 // Package verbose provides a simple package to allow for printing out messages that help you show the flow of how your program works.
 // And you don't have to remove them. Just implement it as a 'flag':
 // verb:=verbose.New()
@@ -17,84 +19,37 @@ package verbose
 import (
 	"fmt"
 	"io"
-	"path/filepath"
-	"runtime"
-	"time"
 )
 
 // Just like fmt.Fprint -- only prints when verbose.V is true.  Only prints the date and line number if PrintDate and PrintLine are true
 func (verb *Verb) Fprint(w io.Writer, a ...any) {
-	if verb.V {
-		if verb.Delimeter == "" {
-			verb.Delimeter = " "
-		}
-		if verb.PrintDate == true {
-			tn := time.Now().Format(verb.Dformat)
-			fmt.Printf("%s%s", tn, verb.Delimeter)
-		}
-		if verb.PrintLine {
-			_, file, line, ok := runtime.Caller(1)
-			if !ok {
-				file = "???"
-				line = 0
-			} else {
-				file = filepath.Base(file)
-			}
-			fmt.Fprintf(w, "%s:%d%s", file, line, verb.Delimeter)
-		}
-		fmt.Fprint(w, a...)
+	verb.hit()
+	if verb.Enabled() {
+		b := getBuf()
+		*b = fmt.Append(*b, a...)
+		verb.print(w, *b)
+		putBuf(b)
 	}
 }
 
-func MyTest() string {
-	return "Successfull"
-}
-
 // Just like fmt.Fprintln -- only prints when verbose.V is true. Only prints the date and line number if PrintDate and PrintLine are true
 func (verb *Verb) Fprintln(w io.Writer, a ...any) {
-	if verb.V {
-		if verb.Delimeter == "" {
-			verb.Delimeter = " "
-		}
-		if verb.PrintDate == true {
-			tn := time.Now().Format(verb.Dformat)
-			fmt.Printf("%s%s", tn, verb.Delimeter)
-		}
-		if verb.PrintLine {
-			_, file, line, ok := runtime.Caller(1)
-			if !ok {
-				file = "???"
-				line = 0
-			} else {
-				file = filepath.Base(file)
-			}
-			fmt.Fprintf(w, "%s:%d%s", file, line, verb.Delimeter)
-		}
-		fmt.Fprintln(w, a...)
+	verb.hit()
+	if verb.Enabled() {
+		b := getBuf()
+		*b = fmt.Appendln(*b, a...)
+		verb.print(w, *b)
+		putBuf(b)
 	}
 }
 
 // Just like fmt.Fprintf, but only prints if verb.V is true. Only prints the date and line number if PrintDate and PrintLine are true
 func (verb *Verb) Fprintf(w io.Writer, format string, a ...any) {
-	if verb.V {
-		if verb.Delimeter == "" {
-			verb.Delimeter = " "
-		}
-		if verb.PrintDate == true {
-			tn := time.Now().Format(verb.Dformat)
-			fmt.Printf("%s%s", tn, verb.Delimeter)
-		}
-
-		if verb.PrintLine {
-			_, file, line, ok := runtime.Caller(1)
-			if !ok {
-				file = "???"
-				line = 0
-			} else {
-				file = filepath.Base(file)
-			}
-			fmt.Fprintf(w, "%s:%d%s", file, line, verb.Delimeter)
-		}
-		fmt.Fprintf(w, format, a...)
+	verb.hit()
+	if verb.Enabled() {
+		b := getBuf()
+		*b = fmt.Appendf(*b, format, a...)
+		verb.print(w, *b)
+		putBuf(b)
 	}
 }
@@ -0,0 +1,65 @@
+package verbose
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sync"
+)
+
+// Writer returns an io.Writer for libraries that write their debug output to one. Each line written to it is printed
+// like Println: only when V is true, with the date and delimiter. A line without its newline waits for the rest.
+// With PrintLine the line number is that of the code calling Write, which for a log.Logger is inside package log;
+// give the Logger log.Lshortfile to see its caller. It can be the Out of another Verb.
+func (v *Verb) Writer() io.Writer {
+	return &lineWriter{v: v}
+}
+
+// StdLog returns a *log.Logger that prints through the Verb. It has no prefix or flags, since the Verb adds the date.
+func (v *Verb) StdLog() *log.Logger {
+	return log.New(v.Writer(), "", 0)
+}
+
+// RedirectLog sends the output of the standard log package through the Verb, so it is only printed in verbose mode.
+// The log flags are cleared while it is redirected. restore puts back the previous output and flags.
+//
+//	defer verb.RedirectLog()()
+func (v *Verb) RedirectLog() (restore func()) {
+	out, flags := log.Writer(), log.Flags()
+	log.SetOutput(v.Writer())
+	log.SetFlags(0)
+	return func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+	}
+}
+
+// lineWriter is the io.Writer returned by Writer.
+type lineWriter struct {
+	v   *Verb
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if !verboseBuild {
+		return len(p), nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	on := w.v.Enabled()
+	rest := w.buf
+	for {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			break
+		}
+		if on {
+			w.v.print(nil, rest[:i+1])
+		}
+		rest = rest[i+1:]
+	}
+	w.buf = append(w.buf[:0], rest...)
+	return len(p), nil
+}
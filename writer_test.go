@@ -0,0 +1,80 @@
+package verbose
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestVerb_Writer(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.Delimeter = "|"
+	w := v.Writer()
+	fmt.Fprintln(w, "not verbose")
+	v.V = true
+	fmt.Fprint(w, "one\ntw")
+	fmt.Fprint(w, "o\nthree")
+	if got, want := buf.String(), "one\ntwo\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	v.PrintDate = true
+	v.Dformat = "date"
+	v.StdLog().Printf("from %s", "log")
+	if got, want := buf.String(), "date|from log\n"; got != want {
+		t.Errorf("StdLog got %q, want %q", got, want)
+	}
+}
+
+func TestVerb_RedirectLog(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	v := New(&buf)
+	v.V = true
+	out, flags := log.Writer(), log.Flags()
+	restore := v.RedirectLog()
+	log.Println("redirected")
+	restore()
+	if got := buf.String(); got != "redirected\n" {
+		t.Errorf("got %q", got)
+	}
+	if log.Writer() != out || log.Flags() != flags {
+		t.Error("restore did not put back the log output and flags")
+	}
+}
+
+func TestVerb_WriterChain(t *testing.T) {
+	needsVerbose(t)
+	var buf bytes.Buffer
+	b := New(&buf)
+	b.V = true
+	b.Delimeter = "|"
+	b.PrintDate = true
+	b.Dformat = "b"
+	a := New(b.Writer())
+	a.V = true
+	a.Delimeter = "|"
+	a.PrintDate = true
+	a.Dformat = "a"
+	done := make(chan struct{})
+	go func() {
+		a.Println("x")
+		restore := a.RedirectLog()
+		log.Println("from log")
+		restore()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("printing through another Verb's Writer deadlocked")
+	}
+	if got, want := buf.String(), "b|a|x\nb|a|from log\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}